@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Audit пишет событие аудита. В отличие от обычных записей оно не фильтруется
+// по уровню, выводится синхронно (буфер сбрасывается сразу после записи)
+// и помечается полем audit=true. Если задан Config.AuditFile или AuditWriter,
+// события пишутся туда, иначе — в основной вывод; очередь NonBlockingStdout
+// события аудита не отбрасывает, а ждёт их вывода.
+func (l *Logger) Audit(action string, fields map[string]any) {
+	extra := make(map[string]any, len(fields)+1)
+	maps.Copy(extra, fields)
+	extra["audit"] = true
+
+	r := l.newRecord(1, INFO, action, extra)
+	// без MaxLineBytes: событие аудита нельзя обрезать
+	line := l.renderRecord(r)
+
+	out := l.audit
+	if out == nil {
+		out = l.sink
+	}
+
+	l.mu.Lock()
+	_, err := out.writeSync([]byte(line + l.terminator()))
+	l.mu.Unlock()
+
+	if err != nil {
+		// событие аудита нельзя потерять молча
+		reportError(fmt.Errorf("audit: %w: %s", err, line))
+	}
+
+	if l.ring != nil {
+		l.ring.add(r.Entry)
+	}
+	l.fireHooks(r.Entry)
+}
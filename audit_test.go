@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAuditIsNotTruncated(t *testing.T) {
+	var b strings.Builder
+	l := New(Config{Output: &b, Format: FormatJSON, DisableTimestamp: true, MaxLineBytes: 80})
+
+	l.Audit("user.delete", map[string]any{"by": "admin", "user_id": 42, "reason": strings.Repeat("x", 100)})
+
+	line := strings.TrimSpace(b.String())
+	var m map[string]any
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		t.Fatalf("invalid JSON %s: %v", line, err)
+	}
+	if m["message"] != "user.delete" || m["by"] != "admin" || m["user_id"] != 42.0 || m["reason"] != strings.Repeat("x", 100) {
+		t.Fatalf("audit event must be written in full: %s", line)
+	}
+	if _, ok := m[truncatedMarker]; ok {
+		t.Fatalf("audit event must not be truncated: %s", line)
+	}
+}
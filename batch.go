@@ -0,0 +1,73 @@
+package logger
+
+import "strings"
+
+// LogBatch пишет записи уровня level одной операцией записи под одной
+// блокировкой. У каждой записи используются Message и Fields (вместе
+// с полями логгера), а также Time и Caller, если они заданы.
+// Уровень фильтруется один раз для всего пакета; CollapseRepeats к пакету
+// не применяется. Пакет уровня FATAL завершает процесс после записи.
+func (l *Logger) LogBatch(level Level, entries []Entry) {
+	if l.logBatch(level, entries) && level == FATAL {
+		l.exit()
+	}
+}
+
+// LogBatchNoExit как LogBatch, но FATAL не завершает процесс: для адаптеров
+// вроде zapadapter, где завершение берёт на себя вызывающая библиотека
+func (l *Logger) LogBatchNoExit(level Level, entries []Entry) {
+	l.logBatch(level, entries)
+}
+
+// logBatch пишет пакет и сообщает, была ли записана хоть одна запись
+func (l *Logger) logBatch(level Level, entries []Entry) bool {
+	if !l.enabled(level) || len(entries) == 0 {
+		return false
+	}
+
+	l.flushPending(level)
+
+	records := make([]record, 0, len(entries))
+	var lines strings.Builder
+	for _, e := range entries {
+		r := l.newRecord(2, level, e.Message, e.Fields)
+		if !e.Time.IsZero() {
+			r.Time = e.Time
+			if l.loc != nil {
+				r.Time = r.Time.In(l.loc)
+			}
+		}
+		if e.Caller != "" {
+			r.Caller = e.Caller
+		}
+
+		r, ok := l.applyMiddleware(r)
+		if !ok || l.sampled(r) || l.deferRecord(r) {
+			continue
+		}
+		if len(records) > 0 {
+			lines.WriteString(l.terminator())
+		}
+		lines.WriteString(l.render(r))
+		records = append(records, r)
+	}
+	if len(records) == 0 {
+		return false
+	}
+
+	err := l.write(level, lines.String())
+	for _, r := range records {
+		werr := err
+		if cerr := l.writeCores(r); werr == nil {
+			werr = cerr
+		}
+		if werr != nil {
+			l.drop(r.Entry, werr)
+		}
+		if l.ring != nil {
+			l.ring.add(r.Entry)
+		}
+		l.fireHooks(r.Entry)
+	}
+	return true
+}
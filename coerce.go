@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Coercer приводит значение поля к виду для вывода; ok == false — тип
+// не его, значение передаётся дальше
+type Coercer func(v any) (out any, ok bool)
+
+var (
+	coercersMu sync.Mutex
+	coercers   atomic.Pointer[[]Coercer] // чтение без блокировки на каждой записи
+)
+
+// RegisterCoercer добавляет правило приведения значений полей во всех
+// форматах, например для своих типов-обёрток. Зарегистрированные правила
+// проверяются по порядку и раньше встроенных (sql.Null*, json.Number,
+// time.Time, net.IP, [16]byte как UUID).
+func RegisterCoercer(fn Coercer) {
+	coercersMu.Lock()
+	defer coercersMu.Unlock()
+
+	var list []Coercer
+	if p := coercers.Load(); p != nil {
+		list = slices.Clone(*p)
+	}
+	list = append(list, fn)
+	coercers.Store(&list)
+}
+
+// coerce применяет зарегистрированные и встроенные правила приведения
+func coerce(v any) any {
+	if p := coercers.Load(); p != nil {
+		for _, fn := range *p {
+			if out, ok := fn(v); ok {
+				return out
+			}
+		}
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		// число в JSON без кавычек; некорректное остаётся строкой
+		if json.Valid([]byte(val)) {
+			return json.RawMessage(val)
+		}
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case net.IP:
+		return val.String()
+	case [16]byte:
+		return formatUUID(val)
+	case driver.Valuer:
+		// sql.NullString и другие sql.Null*: значение или nil, если Valid == false
+		if isNil(v) {
+			return nil
+		}
+		out, err := val.Value()
+		if err != nil {
+			return err.Error()
+		}
+		return out
+	}
+	return v
+}
+
+// formatUUID форматирует 16 байт как UUID: 8-4-4-4-12 шестнадцатеричных цифр
+func formatUUID(b [16]byte) string {
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// loggerKey ключ контекста для логгера, см. IntoContext
+type loggerKey struct{}
+
+// IntoContext возвращает контекст, в котором сохранён логгер
+func (l *Logger) IntoContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext возвращает логгер, сохранённый IntoContext, или логгер по умолчанию
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok && l != nil {
+		return l
+	}
+	return DefaultLogger()
+}
+
+// DefaultContextKeys ключи контекста, которые WithContext читает по умолчанию
+var DefaultContextKeys = []string{"request_id", "user_id"}
+
+// WithContext возвращает дочерний логгер с полями из контекста:
+// по ключам Config.ContextKeys или через Config.ContextExtractor
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	return l.WithFields(l.ctxExtract(ctx))
+}
+
+// KeyExtractor возвращает ContextExtractor, читающий значения контекста
+// по строковым ключам; так работает извлечение по умолчанию
+func KeyExtractor(keys ...string) func(ctx context.Context) map[string]any {
+	return func(ctx context.Context) map[string]any {
+		fields := make(map[string]any, len(keys))
+		for _, key := range keys {
+			if v := ctx.Value(key); v != nil {
+				fields[key] = v
+			}
+		}
+		return fields
+	}
+}
+
+// DebugContext пишет DEBUG с полями из контекста, как WithContext
+func (l *Logger) DebugContext(ctx context.Context, format string, args ...interface{}) {
+	if l.enabled(DEBUG) {
+		l.WithContext(ctx).log(1, DEBUG, fmt.Sprintf(format, args...))
+	}
+}
+
+// InfoContext пишет INFO с полями из контекста, как WithContext
+func (l *Logger) InfoContext(ctx context.Context, format string, args ...interface{}) {
+	if l.enabled(INFO) {
+		l.WithContext(ctx).log(1, INFO, fmt.Sprintf(format, args...))
+	}
+}
+
+// WarnContext пишет WARN с полями из контекста, как WithContext
+func (l *Logger) WarnContext(ctx context.Context, format string, args ...interface{}) {
+	if l.enabled(WARN) {
+		l.WithContext(ctx).log(1, WARN, fmt.Sprintf(format, args...))
+	}
+}
+
+// ErrorContext пишет ERROR с полями из контекста, как WithContext
+func (l *Logger) ErrorContext(ctx context.Context, format string, args ...interface{}) {
+	if l.enabled(ERROR) {
+		l.WithContext(ctx).log(1, ERROR, fmt.Sprintf(format, args...))
+	}
+}
+
+// WarnIfDeadlineSoon пишет WARN, если до дедлайна ctx осталось меньше threshold
+// (или он уже прошёл). К записи добавляются поля контекста (как в WithContext),
+// deadline_soon=true и time_left_ms. Время берётся из Config.Now, если он задан.
+// Возвращает true, если запись была сделана (WARN не отфильтрован уровнем).
+func (l *Logger) WarnIfDeadlineSoon(ctx context.Context, threshold time.Duration, format string, args ...interface{}) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return false
+	}
+
+	left := deadline.Sub(l.now())
+	if left >= threshold {
+		return false
+	}
+
+	cl := l.WithContext(ctx).WithFields(map[string]any{
+		"deadline_soon": true,
+		"time_left_ms":  left.Milliseconds(),
+	})
+	if !cl.enabled(WARN) {
+		return false
+	}
+	cl.log(1, WARN, fmt.Sprintf(format, args...))
+	return true
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarnIfDeadlineSoon(t *testing.T) {
+	deadline := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	now := func() time.Time { return deadline.Add(-500 * time.Millisecond) }
+
+	var b strings.Builder
+	l := New(Config{Output: &b, DisableTimestamp: true, Now: now})
+	if !l.WarnIfDeadlineSoon(ctx, time.Second, "slow") {
+		t.Fatal("want true when the entry is written")
+	}
+	if !strings.Contains(b.String(), "time_left_ms=500") {
+		t.Fatalf("time left must come from Config.Now: %q", b.String())
+	}
+
+	if l.WarnIfDeadlineSoon(ctx, 100*time.Millisecond, "slow") {
+		t.Fatal("want false when the deadline is not close")
+	}
+
+	b.Reset()
+	filtered := New(Config{Output: &b, Level: ERROR, Now: now})
+	if filtered.WarnIfDeadlineSoon(ctx, time.Second, "slow") || b.Len() != 0 {
+		t.Fatalf("want false and no output when WARN is filtered, got %q", b.String())
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import "reflect"
+
+// copyValue возвращает глубокую копию срезов, map и массивов (в том числе
+// вложенных), чтобы последующие изменения у вызывающего не попадали в лог.
+// Остальные значения, включая указатели и структуры, возвращаются как есть.
+func copyValue(v any) any {
+	switch val := v.(type) {
+	case nil, string, bool, int, int64, float64, error:
+		return v
+	case []byte:
+		return append([]byte(nil), val...)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return copyReflect(rv).Interface()
+	}
+	return v
+}
+
+func copyReflect(rv reflect.Value) reflect.Value {
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return rv
+		}
+		out := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out.Index(i).Set(copyReflect(rv.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			out.Index(i).Set(copyReflect(rv.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv
+		}
+		out := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), copyReflect(iter.Value()))
+		}
+		return out
+	case reflect.Interface:
+		if rv.IsNil() {
+			return rv
+		}
+		out := reflect.New(rv.Type()).Elem()
+		out.Set(copyReflect(rv.Elem()))
+		return out
+	}
+	return rv
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// Core дополнительный вывод со своим форматом, см. Config.Cores.
+// Каждая запись, прошедшая фильтр уровня, выводится и в основной вывод,
+// и во все Core.
+type Core struct {
+	Format     Format
+	Color      bool
+	Level      Level     // свой порог поверх Config.Level; 0 (DEBUG) — без доп. фильтра, проходят и уровни ниже DEBUG
+	Writer     io.Writer // куда писать; если nil — в OutputFile или stdout
+	OutputFile string    // файл с ротацией по настройкам Config
+}
+
+// core собранный Core: формат и выход
+type core struct {
+	format Format
+	color  bool
+	level  Level
+	filter bool // задан ли свой порог; без него проходят и уровни ниже DEBUG
+	sink   *output
+}
+
+func newCores(cfg Config) []*core {
+	cores := make([]*core, 0, len(cfg.Cores))
+	for _, c := range cfg.Cores {
+		var sink *output
+		switch {
+		case c.Writer != nil:
+			sink = newOutput(withWriteTimeout(c.Writer, cfg.WriteTimeout), nil, outputOptions{sync: writerSyncer(c.Writer)})
+		case c.OutputFile != "":
+			sink = newFileOutput(FileConfig{Filename: c.OutputFile}, cfg, outputOptions{
+				flushInterval: cfg.FlushInterval,
+				bufferSize:    cfg.WriteBufferSize,
+			})
+		default:
+			sink = newOutput(os.Stdout, nil, outputOptions{})
+		}
+		cores = append(cores, &core{format: c.Format, color: c.Color, level: c.Level, filter: c.Level != 0, sink: sink})
+	}
+	return cores
+}
+
+// newCache создаёт пустой кэш полей, с местом под каждый Core
+func (l *Logger) newCache() *fieldCache {
+	c := &fieldCache{}
+	if len(l.cores) > 0 {
+		c.cores = make([]fieldCache, len(l.cores))
+	}
+	return c
+}
+
+// writeCores выводит запись во все Core в их форматах
+func (l *Logger) writeCores(r record) error {
+	var err error
+	for i, c := range l.cores {
+		if c.filter && r.Level < c.level {
+			continue
+		}
+
+		// тот же логгер, но с форматом Core и его кэшем полей
+		view := *l
+		view.format, view.color = c.format, c.color
+		view.cache = &l.cache.cores[i]
+
+		cr := r
+		if cr.static == l.cache {
+			cr.static = view.cachedFields()
+		}
+		line := view.render(cr)
+
+		l.mu.Lock()
+		_, werr := c.sink.Write([]byte(line + view.terminator()))
+		l.mu.Unlock()
+		if err == nil {
+			err = werr
+		}
+	}
+	return err
+}
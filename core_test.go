@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoreWithoutLevelPassesCustomLevels(t *testing.T) {
+	const trace = Level(-5)
+	RegisterLevel(trace, "TRACE", "")
+
+	var main, all, warn strings.Builder
+	l := New(Config{
+		Output:           &main,
+		Level:            trace,
+		DisableTimestamp: true,
+		Cores: []Core{
+			{Writer: &all},
+			{Writer: &warn, Level: WARN},
+		},
+	})
+	l.Logf(trace, "trace")
+	l.Warn("warn")
+
+	for name, b := range map[string]*strings.Builder{"main": &main, "core without Level": &all} {
+		if !strings.Contains(b.String(), "[TRACE]") || !strings.Contains(b.String(), "[WARN]") {
+			t.Errorf("%s: want TRACE and WARN, got %q", name, b.String())
+		}
+	}
+	if strings.Contains(warn.String(), "[TRACE]") || !strings.Contains(warn.String(), "[WARN]") {
+		t.Errorf("core with Level WARN: want only WARN, got %q", warn.String())
+	}
+}
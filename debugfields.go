@@ -0,0 +1,23 @@
+package logger
+
+import "maps"
+
+// WithDebugField возвращает дочерний логгер с полем, которое выводится
+// только в записях уровня DEBUG и ниже. Записи INFO и выше идут без него.
+func (l *Logger) WithDebugField(key string, value any) *Logger {
+	child := l.clone()
+	child.debugFields = withField(l.debugFields, key, value)
+	return child
+}
+
+// addDebugFields дописывает поля WithDebugField к полям записи
+func (l *Logger) addDebugFields(level Level, extra map[string]any) map[string]any {
+	if level > DEBUG || len(l.debugFields) == 0 {
+		return extra
+	}
+
+	out := make(map[string]any, len(extra)+len(l.debugFields))
+	maps.Copy(out, l.debugFields)
+	maps.Copy(out, extra)
+	return out
+}
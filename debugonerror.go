@@ -0,0 +1,70 @@
+package logger
+
+import "sync"
+
+// defaultDebugOnErrorSize размер буфера DebugOnError, если он не задан
+const defaultDebugOnErrorSize = 100
+
+// debugBuffer записи ниже порога уровня, отложенные до первой ошибки
+type debugBuffer struct {
+	mu      sync.Mutex
+	records []record
+	size    int
+}
+
+// add откладывает запись; при переполнении отбрасывается самая старая
+func (b *debugBuffer) add(r record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.records) == b.size {
+		copy(b.records, b.records[1:])
+		b.records = b.records[:b.size-1]
+	}
+	b.records = append(b.records, r)
+}
+
+// take забирает отложенные записи и очищает буфер
+func (b *debugBuffer) take() []record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	records := b.records
+	b.records = nil
+	return records
+}
+
+// DebugOnError возвращает дочерний логгер для одного запроса: записи ниже
+// порога уровня (обычно DEBUG) не выводятся, а копятся в буфере до size
+// последних. Первая запись уровня ERROR и выше выводит их перед собой,
+// чтобы у ошибки был полный контекст. Если ошибки не было, буфер просто
+// отбрасывается вместе с логгером. size <= 0 — 100 записей.
+// Буфер общий для логгера и его потомков.
+func (l *Logger) DebugOnError(size int) *Logger {
+	if size <= 0 {
+		size = defaultDebugOnErrorSize
+	}
+
+	child := l.clone()
+	child.pending = &debugBuffer{size: size}
+	return child
+}
+
+// deferRecord откладывает запись ниже порога уровня, см. DebugOnError
+func (l *Logger) deferRecord(r record) bool {
+	if l.pending == nil || l.passes(r.Level) {
+		return false
+	}
+	l.pending.add(r)
+	return true
+}
+
+// flushPending выводит отложенные записи перед записью уровня ERROR и выше
+func (l *Logger) flushPending(level Level) {
+	if l.pending == nil || level < ERROR {
+		return
+	}
+	for _, r := range l.pending.take() {
+		l.output(r)
+	}
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// defaultLogger хранит логгер по умолчанию; nil — ещё не создан
+var defaultLogger atomic.Pointer[Logger]
+
+// DefaultLogger возвращает логгер по умолчанию, создавая его при первом обращении
+func DefaultLogger() *Logger {
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+
+	l := New(Config{
+		Level:      INFO,
+		JsonOutput: false,
+		ShowCaller: true,
+		Color:      true,
+		OutputFile: "", // stdout
+	})
+	if defaultLogger.CompareAndSwap(nil, l) {
+		return l
+	}
+	return defaultLogger.Load()
+}
+
+// SetDefault заменяет логгер по умолчанию. nil сбрасывает его,
+// и при следующем обращении будет создан логгер со стандартным конфигом.
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+// Configure создаёт логгер по конфигу и делает его логгером по умолчанию
+func Configure(cfg Config) {
+	SetDefault(New(cfg))
+}
+
+// Debug пишет в логгер по умолчанию
+func Debug(format string, args ...interface{}) {
+	if l := DefaultLogger(); l.enabled(DEBUG) {
+		l.log(1, DEBUG, fmt.Sprintf(format, args...))
+	}
+}
+
+// Info пишет в логгер по умолчанию
+func Info(format string, args ...interface{}) {
+	if l := DefaultLogger(); l.enabled(INFO) {
+		l.log(1, INFO, fmt.Sprintf(format, args...))
+	}
+}
+
+// Warn пишет в логгер по умолчанию
+func Warn(format string, args ...interface{}) {
+	if l := DefaultLogger(); l.enabled(WARN) {
+		l.log(1, WARN, fmt.Sprintf(format, args...))
+	}
+}
+
+// Error пишет в логгер по умолчанию
+func Error(format string, args ...interface{}) {
+	if l := DefaultLogger(); l.enabled(ERROR) {
+		l.log(1, ERROR, fmt.Sprintf(format, args...))
+	}
+}
+
+// Fatal пишет в логгер по умолчанию и завершает процесс
+func Fatal(format string, args ...interface{}) {
+	l := DefaultLogger()
+	l.log(1, FATAL, fmt.Sprintf(format, args...))
+	l.exit()
+}
+
+// Logf пишет в логгер по умолчанию с уровнем, выбранным во время выполнения
+func Logf(level Level, format string, args ...interface{}) {
+	DefaultLogger().logf(2, level, format, args...)
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultHelpersCaller(t *testing.T) {
+	var b strings.Builder
+	prev := defaultLogger.Load()
+	defer defaultLogger.Store(prev)
+	SetDefault(New(Config{Output: &b, Level: DEBUG, ShowCaller: true, DisableTimestamp: true, ExitFunc: func(int) {}}))
+
+	Debug("debug")
+	Info("info")
+	Warn("warn")
+	Error("error")
+	Fatal("fatal")
+	Logf(INFO, "logf")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("want 6 lines, got %d:\n%s", len(lines), b.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "default_test.go:") {
+			t.Errorf("caller must point to the test, got %q", line)
+		}
+	}
+}
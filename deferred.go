@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+// ErrDeferredFull возвращается, если буфер отложенного логгера заполнен
+var ErrDeferredFull = errors.New("logger: deferred buffer full")
+
+// defaultDeferredBufferSize ёмкость буфера Deferred по умолчанию, в байтах
+const defaultDeferredBufferSize = 1 << 20
+
+// deferredBuffer копит выведенные строки до SetOutput
+type deferredBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func newDeferredBuffer(limit int) *deferredBuffer {
+	if limit <= 0 {
+		limit = defaultDeferredBufferSize
+	}
+	return &deferredBuffer{limit: limit}
+}
+
+func (d *deferredBuffer) Write(p []byte) (int, error) {
+	if d.buf.Len()+len(p) > d.limit {
+		return 0, ErrDeferredFull
+	}
+	return d.buf.Write(p)
+}
+
+// Close выводит так и не переданные в SetOutput записи в stderr,
+// чтобы записи раннего старта не пропали
+func (d *deferredBuffer) Close() error {
+	_, err := d.buf.WriteTo(os.Stderr)
+	return err
+}
+
+// SetOutput переключает основной вывод логгера и всех его потомков на w.
+// Отложенные записи (Config.Deferred) выводятся в w перед новыми. Прежний
+// выход сбрасывается и закрывается, если логгер его открывал (OutputFile).
+// Сжатие и буферизация прежнего выхода сохраняются; w логгер не закрывает.
+func (l *Logger) SetOutput(w io.Writer) error {
+	return l.sink.replace(withWriteTimeout(w, l.timeout))
+}
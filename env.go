@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LevelFromEnv читает уровень из переменной окружения varName;
+// если она не задана или не разбирается ParseLevel, возвращает fallback
+func LevelFromEnv(varName string, fallback Level) Level {
+	v, ok := os.LookupEnv(varName)
+	if !ok {
+		return fallback
+	}
+
+	level, err := ParseLevel(v)
+	if err != nil {
+		return fallback
+	}
+	return level
+}
+
+// ConfigFromEnv собирает Config из переменных окружения:
+//
+//	LOG_LEVEL         уровень (debug, info, warn, error, fatal или число), по умолчанию info
+//	LOG_FORMAT        text, json, gcp или proto, по умолчанию text
+//	LOG_FILE          путь к файлу, по умолчанию stdout
+//	LOG_COLOR         цветной вывод (true/false)
+//	LOG_CALLER        показывать место вызова (true/false)
+//	LOG_MAX_SIZE_MB   размер файла для ротации
+//	LOG_MAX_BACKUPS   кол-во резервных файлов
+//	LOG_MAX_AGE_DAYS  возраст файлов в днях
+//	LOG_COMPRESS      сжимать старые файлы (true/false)
+//
+// Некорректные значения пропускаются с сообщением в stderr.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Level:      INFO,
+		OutputFile: os.Getenv("LOG_FILE"),
+	}
+
+	if v, ok := os.LookupEnv("LOG_LEVEL"); ok {
+		level, err := ParseLevel(v)
+		if err != nil {
+			reportError(err)
+		} else {
+			cfg.Level = level
+		}
+	}
+	if v, ok := os.LookupEnv("LOG_FORMAT"); ok {
+		format, err := parseFormat(v)
+		if err != nil {
+			reportError(err)
+		} else {
+			cfg.Format = format
+		}
+	}
+
+	envBool("LOG_COLOR", &cfg.Color)
+	envBool("LOG_CALLER", &cfg.ShowCaller)
+	envBool("LOG_COMPRESS", &cfg.Compress)
+	envInt("LOG_MAX_SIZE_MB", &cfg.MaxSizeMB)
+	envInt("LOG_MAX_BACKUPS", &cfg.MaxBackups)
+	envInt("LOG_MAX_AGE_DAYS", &cfg.MaxAgeDays)
+	return cfg
+}
+
+// NewFromEnv создаёт логгер с настройками ConfigFromEnv
+func NewFromEnv() *Logger {
+	return New(ConfigFromEnv())
+}
+
+func parseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "text", "":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	case "gcp":
+		return FormatGCP, nil
+	case "proto":
+		return FormatProto, nil
+	}
+	return 0, fmt.Errorf("logger: unknown format %q", s)
+}
+
+func envBool(name string, dst *bool) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		reportError(fmt.Errorf("%s: %w", name, err))
+		return
+	}
+	*dst = b
+}
+
+func envInt(name string, dst *int) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		reportError(fmt.Errorf("%s: %w", name, err))
+		return
+	}
+	*dst = n
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithError возвращает дочерний логгер с полем error. Для обёрнутых ошибок
+// добавляется error_chain — сообщения всей цепочки errors.Unwrap, а если
+// в цепочке есть ошибка с методом StackTrace() (как в github.com/pkg/errors),
+// то и stacktrace самой глубокой из них.
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l
+	}
+
+	fields := map[string]any{"error": err.Error()}
+
+	var chain []string
+	var stack string
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		chain = append(chain, e.Error())
+		if st, ok := stackTrace(e); ok {
+			stack = st
+		}
+	}
+
+	if len(chain) > 1 {
+		fields["error_chain"] = chain
+	}
+	if stack != "" {
+		fields["stacktrace"] = stack
+	}
+	return l.WithFields(fields)
+}
+
+// stackTrace вызывает у ошибки метод StackTrace() без аргументов, если он есть.
+// Тип результата у разных библиотек свой, поэтому метод ищется через reflect.
+func stackTrace(err error) (string, bool) {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return "", false
+	}
+
+	st := strings.TrimSpace(fmt.Sprintf("%+v", m.Call(nil)[0].Interface()))
+	return st, st != ""
+}
@@ -0,0 +1,43 @@
+package logger
+
+import "sync"
+
+var (
+	eventsMu sync.RWMutex
+	events   map[string]struct{} // nil — разрешены любые события
+)
+
+// RegisterEvents добавляет допустимые значения поля event. Пока не
+// зарегистрировано ни одного имени, Event принимает любые.
+func RegisterEvents(names ...string) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	if events == nil {
+		events = make(map[string]struct{}, len(names))
+	}
+	for _, name := range names {
+		events[name] = struct{}{}
+	}
+}
+
+// Event возвращает дочерний логгер с полем event=name. Если имя не
+// зарегистрировано через RegisterEvents, вместо event пишется invalid_event=name,
+// чтобы опечатки было видно при анализе логов.
+func (l *Logger) Event(name string) *Logger {
+	if !validEvent(name) {
+		return l.WithField("invalid_event", name)
+	}
+	return l.WithField("event", name)
+}
+
+func validEvent(name string) bool {
+	eventsMu.RLock()
+	defer eventsMu.RUnlock()
+
+	if events == nil {
+		return true
+	}
+	_, ok := events[name]
+	return ok
+}
@@ -0,0 +1,17 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+)
+
+// eventIDEncoding base32 без паддинга: ID легко продиктовать и найти grep
+var eventIDEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newEventID возвращает короткий случайный ID записи из 64 бит,
+// например OHQ5YKY2KRLEA
+func newEventID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return eventIDEncoding.EncodeToString(b[:])
+}
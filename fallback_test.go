@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFallbackToStdout(t *testing.T) {
+	// каталог вывода — обычный файл: запись не удаётся даже от root,
+	// в отличие от каталога без прав на запись
+	notDir := filepath.Join(t.TempDir(), "not-a-dir")
+	if err := os.WriteFile(notDir, nil, 0o444); err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	var errs []error
+	dropped := 0
+	l := New(Config{
+		OutputFile:       filepath.Join(notDir, "app.log"),
+		DisableTimestamp: true,
+		FallbackToStdout: true,
+		ErrorHandler:     func(err error) { errs = append(errs, err) },
+		OnDrop:           func(Entry) { dropped++ },
+	})
+	l.Info("still visible")
+	l.Close()
+
+	w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+
+	if !strings.Contains(string(out), "still visible") {
+		t.Fatalf("entry not written to stdout, got %q", out)
+	}
+	if len(errs) == 0 {
+		t.Fatal("ErrorHandler must receive the write error")
+	}
+	if dropped != 0 {
+		t.Fatalf("entry written to stdout must not be reported as dropped, OnDrop called %d times", dropped)
+	}
+}
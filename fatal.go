@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultFatalTimeout сколько Fatal ждёт завершения хуков OnFatal
+const defaultFatalTimeout = 5 * time.Second
+
+// fatalHooks общие для логгера и потомков хуки, выполняемые перед выходом
+type fatalHooks struct {
+	mu      sync.Mutex
+	hooks   []func()
+	exit    func(int)
+	timeout time.Duration
+}
+
+func newFatalHooks(exit func(int), timeout time.Duration) *fatalHooks {
+	if exit == nil {
+		exit = os.Exit
+	}
+	if timeout <= 0 {
+		timeout = defaultFatalTimeout
+	}
+	return &fatalHooks{exit: exit, timeout: timeout}
+}
+
+// OnFatal регистрирует функцию, вызываемую в Fatal перед выходом из процесса:
+// сбросить метрики, закрыть пулы соединений, отправить алерт. Хуки выполняются
+// в обратном порядке регистрации; на все вместе отводится Config.FatalTimeout.
+// Хуки общие для логгера и всех его потомков.
+func (l *Logger) OnFatal(fn func()) {
+	l.fatal.mu.Lock()
+	defer l.fatal.mu.Unlock()
+
+	l.fatal.hooks = append(l.fatal.hooks, fn)
+}
+
+// exit выполняет хуки OnFatal, сбрасывает вывод и завершает процесс через ExitFunc
+func (l *Logger) exit() {
+	l.fatal.mu.Lock()
+	hooks := append([]func(){}, l.fatal.hooks...)
+	l.fatal.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			runFatalHook(hooks[i])
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(l.fatal.timeout):
+	}
+
+	l.eachOutput((*output).Flush)
+	l.fatal.exit(1)
+}
+
+// runFatalHook не даёт панике в хуке помешать выходу
+func runFatalHook(fn func()) {
+	defer func() { recover() }()
+	fn()
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"maps"
+	"slices"
+)
+
+// WithFieldsFunc возвращает дочерний логгер, который вызывает fn при каждой
+// записи и добавляет возвращённые поля, например значения из хранилища
+// текущего запроса. В отличие от WithFields, поля вычисляются в момент
+// записи, а не при создании логгера. При совпадении ключей поля fn важнее
+// полей WithFields.
+func (l *Logger) WithFieldsFunc(fn func() map[string]any) *Logger {
+	if fn == nil {
+		return l
+	}
+	child := l.clone()
+	child.fieldFuncs = append(slices.Clip(l.fieldFuncs), fn)
+	return child
+}
+
+// addFuncFields дописывает поля WithFieldsFunc к полям записи
+func (l *Logger) addFuncFields(extra map[string]any) map[string]any {
+	if len(l.fieldFuncs) == 0 {
+		return extra
+	}
+
+	out := make(map[string]any, len(extra))
+	for _, fn := range l.fieldFuncs {
+		maps.Copy(out, fn())
+	}
+	maps.Copy(out, extra)
+	return out
+}
@@ -0,0 +1,32 @@
+package logger
+
+// DebugFields пишет DEBUG без сообщения, только с полями — для событий,
+// у которых нет текста для человека
+func (l *Logger) DebugFields(fields map[string]any) {
+	if l.enabled(DEBUG) {
+		l.WithFields(fields).log(1, DEBUG, "")
+	}
+}
+
+// InfoFields пишет INFO без сообщения, только с полями:
+//
+//	log.InfoFields(map[string]any{"event": "cache_miss", "key": k})
+func (l *Logger) InfoFields(fields map[string]any) {
+	if l.enabled(INFO) {
+		l.WithFields(fields).log(1, INFO, "")
+	}
+}
+
+// WarnFields пишет WARN без сообщения, только с полями
+func (l *Logger) WarnFields(fields map[string]any) {
+	if l.enabled(WARN) {
+		l.WithFields(fields).log(1, WARN, "")
+	}
+}
+
+// ErrorFields пишет ERROR без сообщения, только с полями
+func (l *Logger) ErrorFields(fields map[string]any) {
+	if l.enabled(ERROR) {
+		l.WithFields(fields).log(1, ERROR, "")
+	}
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileConfig файл вывода со своими настройками ротации;
+// нулевые значения берутся из общих настроек Config
+type FileConfig struct {
+	Filename   string
+	MaxSizeMB  int  // макс размер файла для ротации (MB)
+	MaxBackups int  // кол-во резервных файлов
+	MaxAgeDays int  // максимальный возраст файла в днях
+	Compress   bool // сжимать старые файлы
+}
+
+// newFileOutput создаёт выход в файл с ротацией lumberjack
+func newFileOutput(fc FileConfig, cfg Config, opts outputOptions) *output {
+	if fc.MaxSizeMB == 0 {
+		fc.MaxSizeMB = cfg.MaxSizeMB
+	}
+	if fc.MaxBackups == 0 {
+		fc.MaxBackups = cfg.MaxBackups
+	}
+	if fc.MaxAgeDays == 0 {
+		fc.MaxAgeDays = cfg.MaxAgeDays
+	}
+	fc.Compress = fc.Compress || cfg.Compress
+
+	if err := prepareFile(fc.Filename, cfg.FileMode, cfg.FileOwner, cfg.FileGroup); err != nil {
+		reportError(err)
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   fc.Filename,
+		MaxSize:    fc.MaxSizeMB,
+		MaxBackups: fc.MaxBackups,
+		MaxAge:     fc.MaxAgeDays,
+		Compress:   fc.Compress,
+	}
+	opts.sync = fileSyncer(fc.Filename)
+	opts.reopen = func() error {
+		// следующая запись откроет файл по имени заново; если старый файл
+		// переименован, новый создаётся здесь, чтобы сохранить FileMode и владельца
+		if err := file.Close(); err != nil {
+			return err
+		}
+		return prepareFile(fc.Filename, cfg.FileMode, cfg.FileOwner, cfg.FileGroup)
+	}
+	return newOutput(file, file, opts)
+}
+
+// prepareFile заранее создаёт файл лога с нужными правами и владельцем.
+// lumberjack открывает существующий файл как есть, а при ротации копирует
+// права и владельца старого файла, поэтому этого достаточно и для новых файлов.
+func prepareFile(path string, mode os.FileMode, owner, group string) error {
+	if mode == 0 && owner == "" && group == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	createMode := mode
+	if createMode == 0 {
+		createMode = 0o600 // как у lumberjack
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, createMode)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	if mode != 0 {
+		// OpenFile учитывает umask, а у существующего файла права могли быть другими
+		if err := os.Chmod(path, mode); err != nil {
+			return err
+		}
+	}
+
+	if owner == "" && group == "" {
+		return nil
+	}
+	uid, gid := -1, -1
+	if owner != "" {
+		if uid, err = lookupID(owner, func(name string) (string, error) {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			return u.Uid, nil
+		}); err != nil {
+			return fmt.Errorf("file owner %q: %w", owner, err)
+		}
+	}
+	if group != "" {
+		if gid, err = lookupID(group, func(name string) (string, error) {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		}); err != nil {
+			return fmt.Errorf("file group %q: %w", group, err)
+		}
+	}
+	return os.Chown(path, uid, gid)
+}
+
+// lookupID принимает числовой id или имя, которое разрешается через lookup
+func lookupID(nameOrID string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		return id, nil
+	}
+	id, err := lookup(nameOrID)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(id)
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReopenKeepsFileMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l := New(Config{OutputFile: path, FileMode: 0o640})
+	defer l.Close()
+
+	l.Info("before rotation")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("after rotation")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o640 {
+		t.Fatalf("reopened file mode = %v, want -rw-r-----", mode)
+	}
+}
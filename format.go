@@ -0,0 +1,498 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// TimeKitchenMillis только время с миллисекундами, для локальной разработки
+const TimeKitchenMillis = "15:04:05.000"
+
+// Format формат вывода записей
+type Format int
+
+const (
+	FormatText  Format = iota // читаемый текст, по умолчанию
+	FormatJSON                // одна JSON-запись на строку
+	FormatGCP                 // структурированный JSON для Google Cloud Logging
+	FormatProto               // protobuf LogEntry с префиксом длины, см. пакет logpb
+)
+
+// String возвращает имя формата, как в LOG_FORMAT
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatJSON:
+		return "json"
+	case FormatGCP:
+		return "gcp"
+	case FormatProto:
+		return "proto"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// KeyCollision что делать с пользовательским полем, чей ключ совпадает со встроенным
+type KeyCollision int
+
+const (
+	CollisionPrefix    KeyCollision = iota // переименовать в fields.<ключ>, по умолчанию
+	CollisionDrop                          // отбросить поле
+	CollisionOverwrite                     // перезаписать встроенный ключ (старое поведение)
+)
+
+// reservedKeys встроенные ключи записи для каждого формата
+var reservedKeys = map[Format][]string{
+	FormatText: {"time", "level", "message", "caller"},
+	FormatJSON: {"time", "level", "message", "caller"},
+	FormatGCP:  {"time", "severity", "message", "logging.googleapis.com/sourceLocation"},
+}
+
+// resolveCollisions применяет политику KeyCollision к полям записи
+func (l *Logger) resolveCollisions(fields map[string]any) map[string]any {
+	if l.collision == CollisionOverwrite || len(fields) == 0 {
+		return fields
+	}
+
+	reserved := reservedKeys[l.format]
+	if l.prefix != "" && l.format != FormatText {
+		reserved = append(slices.Clip(reserved), "prefix")
+	}
+
+	var resolved map[string]any
+	for _, key := range reserved {
+		v, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if resolved == nil {
+			resolved = make(map[string]any, len(fields))
+			for k, v := range fields {
+				resolved[k] = v
+			}
+		}
+		delete(resolved, key)
+		if l.collision == CollisionPrefix {
+			resolved["fields."+key] = v
+		}
+	}
+
+	if resolved == nil {
+		return fields
+	}
+	return resolved
+}
+
+// fieldCache заранее сериализованные поля логгера. Поля логгера не меняются
+// после создания, поэтому фрагмент строится один раз при первой записи
+// и пересоздаётся только вместе с полями (WithFields).
+type fieldCache struct {
+	once     sync.Once
+	fields   map[string]any // после ReplaceField и KeyCollision
+	fragment string         // поля в формате логгера, без обрамления
+	cores    []fieldCache   // кэши для форматов Config.Cores
+}
+
+// cachedFields возвращает обработанные и сериализованные поля логгера
+func (l *Logger) cachedFields() *fieldCache {
+	c := l.cache
+	c.once.Do(func() {
+		c.fields = l.prepareFields(l.fields)
+		c.fragment = l.encodeFields(c.fields)
+	})
+	return c
+}
+
+// render превращает запись в строку в формате логгера с учётом MaxLineBytes
+func (l *Logger) render(r record) string {
+	line := l.renderRecord(r)
+	if l.maxLine > 0 && len(line) > l.maxLine && l.format != FormatProto {
+		return l.truncate(r, line)
+	}
+	return line
+}
+
+func (l *Logger) renderRecord(r record) string {
+	fields := r.static.fragment
+	if len(r.extra) > 0 {
+		fields = l.joinFields(r.static, r.extra)
+	}
+
+	var msg any = r.Message
+	if r.rawMessage {
+		msg = json.RawMessage(r.Message)
+	}
+
+	switch l.format {
+	case FormatJSON:
+		return l.formatJSON(r.Entry, msg, fields)
+	case FormatGCP:
+		return l.formatGCP(r.Entry, msg, fields)
+	case FormatProto:
+		return l.formatProto(r.Entry, fields)
+	default:
+		return l.formatText(r.Entry, fields)
+	}
+}
+
+// joinFields дописывает к кэшированному фрагменту поля записи. Если ключи
+// пересекаются, фрагмент строится заново, чтобы в JSON не было дублей.
+func (l *Logger) joinFields(static *fieldCache, extra map[string]any) string {
+	for k := range extra {
+		if _, ok := static.fields[k]; ok {
+			merged := make(map[string]any, len(static.fields)+len(extra))
+			maps.Copy(merged, static.fields)
+			maps.Copy(merged, extra)
+			return l.encodeFields(merged)
+		}
+	}
+
+	dynamic := l.encodeFields(extra)
+	switch {
+	case static.fragment == "":
+		return dynamic
+	case l.format == FormatText && !l.textJSON:
+		return static.fragment + " " + dynamic
+	case l.format == FormatProto:
+		return static.fragment + dynamic
+	default:
+		return static.fragment + "," + dynamic
+	}
+}
+
+// encodeFields сериализует поля в формате логгера: k=v через пробел для текста
+// или "k":v через запятую для JSON и TextJSONFields. Ключи идут в алфавитном порядке.
+func (l *Logger) encodeFields(fields map[string]any) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	if l.flatten && l.format == FormatText {
+		fields = flattenMaps(fields)
+	}
+
+	keys := slices.Sorted(maps.Keys(fields))
+
+	if l.format == FormatProto {
+		return l.encodeProtoFields(keys, fields)
+	}
+	if l.format != FormatText || l.textJSON {
+		var b strings.Builder
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONPair(&b, k, l.jsonFieldValue(fields[k]))
+		}
+		return b.String()
+	}
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		raw := fields[k]
+		v := quoteValue(fmt.Sprintf("%v", l.textFieldValue(raw)))
+		if l.color && l.colorizer != nil {
+			if ansi, ok := l.colorizer(k, raw); ok {
+				// цвет строки восстанавливается в formatText, см. colorDefault
+				v = ansi + v + colorDefault
+			}
+		}
+		k = quoteValue(k)
+		if l.color && l.colorKeys {
+			// \033[22m снимает только приглушение, цвет строки сохраняется
+			k = colorDim + k + colorNormal
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return strings.Join(parts, " ")
+}
+
+// flattenMaps разворачивает вложенные map в ключи через точку:
+// user={id:1} становится user.id=1 (FlattenFields)
+func flattenMaps(fields map[string]any) map[string]any {
+	nested := false
+	for _, v := range fields {
+		if reflect.ValueOf(v).Kind() == reflect.Map {
+			nested = true
+			break
+		}
+	}
+	if !nested {
+		return fields
+	}
+
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		flattenValue(out, k, v, 0)
+	}
+	return out
+}
+
+func flattenValue(out map[string]any, key string, v any, depth int) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Len() == 0 || depth >= maxStructDepth {
+		out[key] = v
+		return
+	}
+	iter := rv.MapRange()
+	for iter.Next() {
+		flattenValue(out, fmt.Sprintf("%s.%v", key, iter.Key().Interface()), iter.Value().Interface(), depth+1)
+	}
+}
+
+// quoteValue заключает значение в кавычки в стиле logfmt, если без них
+// его нельзя однозначно разобрать: пробелы, '=', кавычки, управляющие символы
+func quoteValue(s string) string {
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// escapeLine экранирует управляющие символы и переводы строк в стиле Go
+// (\n, \t, \x00), чтобы текстовая запись оставалась одной строкой
+func escapeLine(s string) string {
+	if !strings.ContainsFunc(s, isLineBreaking) {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if isLineBreaking(r) {
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isLineBreaking(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029'
+}
+
+func needsQuoting(s string) bool {
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSONPair пишет "key":value
+func writeJSONPair(b *strings.Builder, key string, value any) {
+	writeJSONString(b, key)
+	b.WriteByte(':')
+	writeJSONValue(b, value)
+}
+
+// builtin применяет ReplaceField к встроенному ключу, если включён ReplaceBuiltin.
+// ok == false означает, что ключ нужно пропустить.
+func (l *Logger) builtin(key string, value any) (string, any, bool) {
+	if l.replaceField == nil || !l.replaceBuiltin {
+		return key, value, true
+	}
+	key, value = l.replaceField(key, value)
+	return key, value, key != ""
+}
+
+// addBuiltin добавляет встроенный ключ в JSON-запись с учётом ReplaceField
+func (l *Logger) addBuiltin(o *jsonObject, key string, value any) {
+	if key, value, ok := l.builtin(key, value); ok {
+		o.add(key, value)
+	}
+}
+
+// prepareFields готовит поля к выводу: ReplaceField, NilOmit,
+// RedactPatterns и KeyCollision
+func (l *Logger) prepareFields(fields map[string]any) map[string]any {
+	return l.resolveCollisions(l.redactFields(l.omitNil(l.replaceFields(fields))))
+}
+
+// replaceFields применяет ReplaceField к пользовательским полям
+func (l *Logger) replaceFields(fields map[string]any) map[string]any {
+	if l.replaceField == nil || len(fields) == 0 {
+		return fields
+	}
+
+	replaced := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if k, v = l.replaceField(k, v); k != "" {
+			replaced[k] = v
+		}
+	}
+	return replaced
+}
+
+func (l *Logger) formatJSON(e Entry, msg any, fields string) string {
+	var b jsonObject
+	if l.prefix != "" {
+		l.addBuiltin(&b, "prefix", l.prefix)
+	}
+	if !l.noTime {
+		l.addBuiltin(&b, "time", e.Time.Format(l.timeFormat))
+	}
+	l.addBuiltin(&b, "level", e.Level.String())
+	l.addBuiltin(&b, "message", msg)
+	if e.Caller != "" {
+		l.addBuiltin(&b, "caller", e.Caller)
+	}
+	return b.close(fields)
+}
+
+// formatGCP рендерит запись в формате structured logging Cloud Logging:
+// https://cloud.google.com/logging/docs/structured-logging
+func (l *Logger) formatGCP(e Entry, msg any, fields string) string {
+	var b jsonObject
+	if l.prefix != "" {
+		l.addBuiltin(&b, "prefix", l.prefix)
+	}
+	if !l.noTime {
+		l.addBuiltin(&b, "time", e.Time.Format(time.RFC3339Nano))
+	}
+	l.addBuiltin(&b, "severity", gcpSeverity(e.Level))
+	l.addBuiltin(&b, "message", msg)
+	if e.Caller != "" {
+		loc := map[string]any{"file": e.Caller}
+		if i := strings.LastIndex(e.Caller, ":"); i >= 0 {
+			loc["file"] = e.Caller[:i]
+			loc["line"] = e.Caller[i+1:]
+		}
+		l.addBuiltin(&b, "logging.googleapis.com/sourceLocation", loc)
+	}
+	return b.close(fields)
+}
+
+// jsonObject собирает JSON-объект из пар и готового фрагмента полей
+type jsonObject struct {
+	b strings.Builder
+}
+
+func (o *jsonObject) add(key string, value any) {
+	if o.b.Len() == 0 {
+		o.b.WriteByte('{')
+	} else {
+		o.b.WriteByte(',')
+	}
+	writeJSONPair(&o.b, key, value)
+}
+
+// close дописывает фрагмент полей и закрывает объект
+func (o *jsonObject) close(fields string) string {
+	if o.b.Len() == 0 {
+		o.b.WriteByte('{')
+	} else if fields != "" {
+		o.b.WriteByte(',')
+	}
+	o.b.WriteString(fields)
+	o.b.WriteByte('}')
+	return o.b.String()
+}
+
+// gcpSeverity переводит уровень в значение LogSeverity Cloud Logging
+func gcpSeverity(level Level) string {
+	switch {
+	case level >= FATAL:
+		return "CRITICAL"
+	case level >= ERROR:
+		return "ERROR"
+	case level >= WARN:
+		return "WARNING"
+	case level >= INFO:
+		return "INFO"
+	case level >= DEBUG:
+		return "DEBUG"
+	default:
+		return "DEFAULT"
+	}
+}
+
+func (l *Logger) formatText(e Entry, fields string) string {
+	var line string
+	add := func(part string) {
+		if line != "" {
+			line += " "
+		}
+		line += part
+	}
+
+	if _, v, ok := l.builtin("level", e.Level.String()); ok {
+		line = fmt.Sprintf("[%v]", v)
+	}
+	level := line
+	if !l.noTime {
+		if _, v, ok := l.builtin("time", e.Time.Format(l.timeFormat)); ok {
+			add(fmt.Sprint(v))
+		}
+	}
+	if e.Caller != "" {
+		if _, v, ok := l.builtin("caller", e.Caller); ok {
+			add(fmt.Sprint(v))
+		}
+	}
+	if _, v, ok := l.builtin("message", e.Message); ok {
+		e.Message = fmt.Sprint(v)
+	} else {
+		e.Message = ""
+	}
+
+	if e.Message != "" {
+		if l.quoteMsg {
+			e.Message = quoteValue(e.Message)
+		} else {
+			e.Message = escapeLine(e.Message)
+		}
+		add(e.Message)
+	}
+	if fields != "" {
+		// без сообщения разделитель не нужен
+		sep := l.fieldSep
+		if e.Message == "" {
+			sep = " "
+		}
+		if l.textJSON {
+			sep, fields = " ", "{"+fields+"}"
+		}
+		line += sep + fields
+	}
+	if l.padLevel && level != "" && line != level {
+		// выравнивание нужно, только если за уровнем что-то есть
+		line = fmt.Sprintf("%-*s", levelWidth()+2, level) + line[len(level):]
+	}
+
+	line = l.colorLine(e.Level, line, fields != "")
+	if l.prefix != "" {
+		// метка для консоли идёт до цвета и не раскрашивается
+		line = l.prefix + " " + line
+	}
+	return line
+}
+
+// colorLine раскрашивает текстовую запись цветом уровня
+func (l *Logger) colorLine(level Level, line string, hasFields bool) string {
+	if !l.color {
+		return line
+	}
+	if color := level.color(); color != "" {
+		if l.colorizer != nil {
+			// после раскрашенного значения возвращаемся к цвету уровня
+			line = strings.ReplaceAll(line, colorDefault, color)
+		}
+		return color + line + colorReset
+	}
+	if (l.colorKeys || l.colorizer != nil) && hasFields {
+		return line + colorReset
+	}
+	return line
+}
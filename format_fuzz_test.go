@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var fuzzSeeds = []string{"", "plain", "with space", "k=v", `"quoted"`, "line\nbreak", "tab\there", "\x00\x1b[31m", " ", "привет", "bad\xffutf8", " | "}
+
+// fuzzLine пишет одну запись и проверяет, что она заняла ровно одну строку
+func fuzzLine(t *testing.T, cfg Config, write func(l *Logger)) string {
+	t.Helper()
+	var b strings.Builder
+	cfg.Output = &b
+	cfg.DisableTimestamp = true
+	write(New(cfg))
+
+	out := b.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "\n") {
+		t.Fatalf("want exactly one line, got %q", out)
+	}
+	return strings.TrimSuffix(out, "\n")
+}
+
+// unquoteTail проверяет, что line = prefix + значение, записанное как есть
+// или в кавычках, которые снимает strconv.Unquote
+func unquoteTail(t *testing.T, line, prefix, want string) {
+	t.Helper()
+	rest, ok := strings.CutPrefix(line, prefix)
+	if !ok {
+		t.Fatalf("line %q does not start with %q", line, prefix)
+	}
+	if strings.HasPrefix(rest, `"`) {
+		got, err := strconv.Unquote(rest)
+		if err != nil {
+			t.Fatalf("value %s does not unquote: %v", rest, err)
+		}
+		rest = got
+	}
+	if rest != want {
+		t.Fatalf("round trip: got %q, want %q (line %q)", rest, want, line)
+	}
+}
+
+func FuzzTextFormat(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s, s)
+	}
+
+	f.Fuzz(func(t *testing.T, msg, val string) {
+		ref := fuzzLine(t, Config{}, func(l *Logger) { l.WithField("v", "x").Info("msg") })
+		line := fuzzLine(t, Config{}, func(l *Logger) { l.WithField("v", val).Info("msg") })
+		unquoteTail(t, line, strings.TrimSuffix(ref, "x"), val)
+
+		fuzzLine(t, Config{}, func(l *Logger) { l.WithField(val, msg).Info("%s", msg) })
+
+		if msg != "" {
+			ref = fuzzLine(t, Config{QuoteMessage: true}, func(l *Logger) { l.Info("x") })
+			line = fuzzLine(t, Config{QuoteMessage: true}, func(l *Logger) { l.Info("%s", msg) })
+			unquoteTail(t, line, strings.TrimSuffix(ref, "x"), msg)
+		}
+	})
+}
+
+func FuzzJSONFormat(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s, s, int64(len(s)))
+	}
+
+	f.Fuzz(func(t *testing.T, msg, key string, n int64) {
+		for _, format := range []Format{FormatJSON, FormatGCP} {
+			line := fuzzLine(t, Config{Format: format}, func(l *Logger) {
+				l.WithFields(map[string]any{key: msg, "n": n, "nested": map[string]any{key: []string{msg}}}).Info("%s", msg)
+			})
+			if !json.Valid([]byte(line)) {
+				t.Fatalf("%v: invalid JSON: %s", format, line)
+			}
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package logger
+
+import "time"
+
+// Entry одна запись лога в том виде, в каком она передаётся хукам
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Caller  string         // файл:строка, если включён ShowCaller
+	Fields  map[string]any // только для чтения
+}
+
+// Hook получает каждую записанную запись, например для отправки во внешнюю систему.
+// Fire вызывается синхронно из log(), поэтому не должен надолго блокироваться.
+type Hook interface {
+	Fire(Entry) error
+}
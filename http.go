@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// secretHeader сообщает, что значение заголовка не должно попадать в лог,
+// даже если заголовок явно разрешён: Authorization, X-Forwarded-Authorization,
+// Cookie, Set-Cookie и т.п.
+func secretHeader(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "authorization") || strings.Contains(name, "cookie")
+}
+
+// RequestFields возвращает поля запроса с едиными для всех сервисов ключами.
+// Заголовки не логируются, кроме перечисленных в headers; значения
+// Authorization и cookie-заголовков и среди них заменяются на [REDACTED].
+func RequestFields(r *http.Request, headers ...string) map[string]any {
+	logged := make(map[string]string, len(headers))
+	for _, k := range headers {
+		k = http.CanonicalHeaderKey(k)
+		v, ok := r.Header[k]
+		if !ok {
+			continue
+		}
+		if secretHeader(k) {
+			logged[k] = "[REDACTED]"
+		} else {
+			logged[k] = strings.Join(v, ", ")
+		}
+	}
+
+	fields := map[string]any{
+		"http_method": r.Method,
+		"http_path":   r.URL.Path,
+		"remote_addr": r.RemoteAddr,
+		"user_agent":  r.UserAgent(),
+	}
+	if len(logged) > 0 {
+		fields["headers"] = logged
+	}
+	if id, ok := RequestIDFromHeaders(r.Header); ok {
+		fields["request_id"] = id
+	}
+	return fields
+}
+
+// LogRequest пишет INFO о входящем запросе с полями RequestFields
+// и заголовками из Config.RequestHeaders
+func (l *Logger) LogRequest(r *http.Request) {
+	if l.enabled(INFO) {
+		l.WithFields(RequestFields(r, l.reqHeaders...)).log(1, INFO, "http request")
+	}
+}
+
+// LogResponse пишет итог обработки запроса: статус и длительность.
+// Уровень зависит от статуса: 5xx — ERROR, 4xx — WARN, остальное — INFO.
+func (l *Logger) LogResponse(r *http.Request, status int, duration time.Duration) {
+	level := INFO
+	switch {
+	case status >= 500:
+		level = ERROR
+	case status >= 400:
+		level = WARN
+	}
+	if !l.enabled(level) {
+		return
+	}
+
+	fields := map[string]any{
+		"http_method": r.Method,
+		"http_path":   r.URL.Path,
+		"status":      status,
+		"duration_ms": duration.Milliseconds(),
+	}
+	if id, ok := RequestIDFromHeaders(r.Header); ok {
+		fields["request_id"] = id
+	}
+	l.WithFields(fields).log(1, level, "http response")
+}
+
+// RequestIDFromHeaders достаёт идентификатор запроса из заголовка X-Request-ID,
+// а если его нет — trace-id из W3C traceparent
+func RequestIDFromHeaders(h http.Header) (string, bool) {
+	if id := strings.TrimSpace(h.Get("X-Request-ID")); id != "" {
+		return id, true
+	}
+
+	// traceparent: version-traceid-parentid-flags, например
+	// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+	parts := strings.Split(strings.TrimSpace(h.Get("traceparent")), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || !isHex(parts[1]) || strings.Trim(parts[1], "0") == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// ContextWithRequestID кладёт идентификатор запроса в контекст так, чтобы его прочитал WithContext
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, "request_id", id)
+}
+
+// ContextFromHeaders дополняет контекст идентификатором запроса из заголовков, если он есть
+func ContextFromHeaders(ctx context.Context, h http.Header) context.Context {
+	if id, ok := RequestIDFromHeaders(h); ok {
+		return ContextWithRequestID(ctx, id)
+	}
+	return ctx
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
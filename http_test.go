@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestFieldsHeadersAreOptIn(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users", nil)
+	r.Header.Set("Accept", "application/json")
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("X-Forwarded-Authorization", "Bearer secret")
+
+	if _, ok := RequestFields(r)["headers"]; ok {
+		t.Fatal("headers must not be logged unless allowlisted")
+	}
+
+	headers := RequestFields(r, "accept", "X-Forwarded-Authorization")["headers"].(map[string]string)
+	if len(headers) != 2 || headers["Accept"] != "application/json" || headers["X-Forwarded-Authorization"] != "[REDACTED]" {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+}
@@ -0,0 +1,34 @@
+package logger
+
+import "context"
+
+// Interface основные методы логгера, чтобы код мог зависеть от интерфейса
+// и подменять логгер в тестах. *Logger приводится к Interface через AsInterface.
+type Interface interface {
+	Debug(format string, args ...interface{})
+	Info(format string, args ...interface{})
+	Warn(format string, args ...interface{})
+	Error(format string, args ...interface{})
+	Fatal(format string, args ...interface{})
+	WithFields(fields map[string]any) Interface
+	WithContext(ctx context.Context) Interface
+}
+
+// AsInterface возвращает l как Interface; дочерние логгеры из WithFields
+// и WithContext тоже реализуют Interface
+func AsInterface(l *Logger) Interface {
+	return asInterface{l}
+}
+
+// asInterface *Logger, у которого With* возвращают Interface
+type asInterface struct {
+	*Logger
+}
+
+func (a asInterface) WithFields(fields map[string]any) Interface {
+	return asInterface{a.Logger.WithFields(fields)}
+}
+
+func (a asInterface) WithContext(ctx context.Context) Interface {
+	return asInterface{a.Logger.WithContext(ctx)}
+}
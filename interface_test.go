@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAsInterface(t *testing.T) {
+	var b strings.Builder
+	var log Interface = AsInterface(New(Config{Output: &b, DisableTimestamp: true, ShowCaller: true}))
+
+	log.WithFields(map[string]any{"user": 1}).WithContext(context.Background()).Info("hello")
+
+	out := b.String()
+	if !strings.Contains(out, "user=1") || !strings.Contains(out, "interface_test.go:") {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
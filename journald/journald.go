@@ -0,0 +1,210 @@
+// Package journald содержит хук vira-logger, отправляющий записи в systemd-journald
+// с полями журнала, доступными для фильтрации через journalctl:
+//
+//	journalctl SYSLOG_IDENTIFIER=auth REQUEST_ID=abc123
+//
+// Если сокет журнала недоступен (не Linux, нет systemd, контейнер без сокета),
+// записи пишутся в Fallback в формате "<приоритет>сообщение", который понимает
+// systemd при захвате stderr.
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	logger "github.com/skrolikov/vira-logger"
+)
+
+// SocketPath стандартный путь к сокету journald
+const SocketPath = "/run/systemd/journal/socket"
+
+// Приоритеты syslog
+const (
+	PriEmerg   = 0
+	PriAlert   = 1
+	PriCrit    = 2
+	PriErr     = 3
+	PriWarning = 4
+	PriNotice  = 5
+	PriInfo    = 6
+	PriDebug   = 7
+)
+
+// Hook реализует logger.Hook для journald
+type Hook struct {
+	Identifier string    // SYSLOG_IDENTIFIER, по умолчанию имя процесса
+	Fallback   io.Writer // куда писать без сокета, по умолчанию os.Stderr
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+// New создаёт хук и подключается к сокету journald. Ошибка подключения
+// не возвращается: хук просто переходит на Fallback, см. Available.
+func New(identifier string) *Hook {
+	if identifier == "" && len(os.Args) > 0 {
+		identifier = os.Args[0][strings.LastIndex(os.Args[0], "/")+1:]
+	}
+
+	h := &Hook{Identifier: identifier, Fallback: os.Stderr}
+	if conn, err := dial(); err == nil {
+		h.conn = conn
+	}
+	return h
+}
+
+// Available сообщает, подключён ли хук к journald
+func (h *Hook) Available() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.conn != nil
+}
+
+// Fire отправляет запись в журнал. Если соединения нет, перед записью
+// хук пробует подключиться заново, так что перезапуск journald не
+// переводит его на Fallback навсегда.
+func (h *Hook) Fire(e logger.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.conn == nil && !h.closed {
+		if conn, err := dial(); err == nil {
+			h.conn = conn
+		}
+	}
+
+	if h.conn != nil {
+		_, err := h.conn.Write(h.encode(e))
+		if err == nil {
+			return nil
+		}
+		// слишком большая датаграмма — сокет жив, в fallback уходит только эта запись
+		if !isMsgSize(err) {
+			h.conn.Close()
+			h.conn = nil
+		}
+	}
+
+	w := h.Fallback
+	if w == nil {
+		w = os.Stderr
+	}
+	_, err := fmt.Fprintf(w, "<%d>%s\n", Priority(e.Level), e.Message)
+	return err
+}
+
+// Close закрывает соединение с journald; после него Fire пишет в Fallback
+func (h *Hook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	if h.conn == nil {
+		return nil
+	}
+	err := h.conn.Close()
+	h.conn = nil
+	return err
+}
+
+// Priority переводит уровень логгера в приоритет syslog
+func Priority(level logger.Level) int {
+	switch {
+	case level >= logger.FATAL:
+		return PriCrit
+	case level >= logger.ERROR:
+		return PriErr
+	case level >= logger.WARN:
+		return PriWarning
+	case level >= logger.INFO:
+		return PriInfo
+	default:
+		return PriDebug
+	}
+}
+
+// encode собирает датаграмму в нативном протоколе журнала:
+// https://systemd.io/JOURNAL_NATIVE_PROTOCOL/
+func (h *Hook) encode(e logger.Entry) []byte {
+	var buf bytes.Buffer
+
+	writeField(&buf, "MESSAGE", e.Message)
+	writeField(&buf, "PRIORITY", fmt.Sprint(Priority(e.Level)))
+	if h.Identifier != "" {
+		writeField(&buf, "SYSLOG_IDENTIFIER", h.Identifier)
+	}
+	if e.Caller != "" {
+		file, line := e.Caller, ""
+		if i := strings.LastIndex(e.Caller, ":"); i >= 0 {
+			file, line = e.Caller[:i], e.Caller[i+1:]
+		}
+		writeField(&buf, "CODE_FILE", file)
+		if line != "" {
+			writeField(&buf, "CODE_LINE", line)
+		}
+	}
+	writeField(&buf, "LEVEL", e.Level.String())
+
+	for k, v := range e.Fields {
+		name := fieldName(k)
+		if name == "" {
+			continue
+		}
+		var value string
+		if err, ok := v.(error); ok {
+			value = err.Error()
+		} else {
+			value = fmt.Sprint(v)
+		}
+		writeField(&buf, name, value)
+	}
+	return buf.Bytes()
+}
+
+// writeField пишет KEY=value или, если в значении есть перевод строки,
+// бинарную форму KEY\n<длина uint64 LE><value>\n
+func writeField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// fieldName приводит ключ поля к имени поля журнала: заглавные латинские
+// буквы, цифры и подчёркивания, не с подчёркивания и не с цифры, до 64 символов
+func fieldName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+
+	name := strings.TrimLeft(b.String(), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "F_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
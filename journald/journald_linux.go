@@ -0,0 +1,17 @@
+//go:build linux
+
+package journald
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+func dial() (net.Conn, error) {
+	return net.Dial("unixgram", SocketPath)
+}
+
+func isMsgSize(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE)
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package journald
+
+import (
+	"errors"
+	"net"
+)
+
+func dial() (net.Conn, error) {
+	return nil, errors.New("journald: supported only on linux")
+}
+
+func isMsgSize(error) bool { return false }
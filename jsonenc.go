@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Кодирование JSON без encoding/json для частых типов значений: строки,
+// числа, bool, nil и json.RawMessage. Остальные типы идут через json.Marshal.
+// В отличие от json.Marshal, символы <, > и & не экранируются.
+
+const hexDigits = "0123456789abcdef"
+
+// writeJSONValue пишет значение поля в JSON
+func writeJSONValue(b *strings.Builder, v any) {
+	var num [32]byte
+	switch val := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case string:
+		writeJSONString(b, val)
+	case bool:
+		b.WriteString(strconv.FormatBool(val))
+	case int:
+		b.Write(strconv.AppendInt(num[:0], int64(val), 10))
+	case int8:
+		b.Write(strconv.AppendInt(num[:0], int64(val), 10))
+	case int16:
+		b.Write(strconv.AppendInt(num[:0], int64(val), 10))
+	case int32:
+		b.Write(strconv.AppendInt(num[:0], int64(val), 10))
+	case int64:
+		b.Write(strconv.AppendInt(num[:0], val, 10))
+	case uint:
+		b.Write(strconv.AppendUint(num[:0], uint64(val), 10))
+	case uint8:
+		b.Write(strconv.AppendUint(num[:0], uint64(val), 10))
+	case uint16:
+		b.Write(strconv.AppendUint(num[:0], uint64(val), 10))
+	case uint32:
+		b.Write(strconv.AppendUint(num[:0], uint64(val), 10))
+	case uint64:
+		b.Write(strconv.AppendUint(num[:0], val, 10))
+	case float32:
+		writeJSONFloat(b, float64(val), 32)
+	case float64:
+		writeJSONFloat(b, val, 64)
+	case json.RawMessage:
+		writeJSONRaw(b, val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			// значение, которое не сериализуется (канал, функция, цикл), пишем строкой
+			writeJSONString(b, fmt.Sprintf("%v", val))
+			return
+		}
+		b.Write(data)
+	}
+}
+
+// writeJSONString пишет строку JSON. Управляющие символы, кавычки и обратная
+// косая черта экранируются, некорректный UTF-8 заменяется на U+FFFD,
+// U+2028 и U+2029 экранируются, как в encoding/json.
+func writeJSONString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case '\n':
+				b.WriteString(`\n`)
+			case '\r':
+				b.WriteString(`\r`)
+			case '\t':
+				b.WriteString(`\t`)
+			default:
+				b.WriteString(`\u00`)
+				b.WriteByte(hexDigits[c>>4])
+				b.WriteByte(hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteString(s[start:i])
+			b.WriteString(`\ufffd`)
+		case r == '\u2028' || r == '\u2029':
+			b.WriteString(s[start:i])
+			b.WriteString(`\u202`)
+			b.WriteByte(hexDigits[r&0xf])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	b.WriteString(s[start:])
+	b.WriteByte('"')
+}
+
+// writeJSONFloat пишет число так же, как encoding/json. NaN и бесконечности
+// в JSON не представимы и пишутся строкой.
+func writeJSONFloat(b *strings.Builder, f float64, bits int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		writeJSONString(b, strconv.FormatFloat(f, 'g', -1, bits))
+		return
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	var num [32]byte
+	out := strconv.AppendFloat(num[:0], f, format, -1, bits)
+	if format == 'e' {
+		// 1e-07 → 1e-7
+		if n := len(out); n >= 4 && out[n-4] == 'e' && out[n-3] == '-' && out[n-2] == '0' {
+			out[n-2] = out[n-1]
+			out = out[:n-1]
+		}
+	}
+	b.Write(out)
+}
+
+// writeJSONRaw пишет готовый JSON в одну строку; некорректный — строкой
+func writeJSONRaw(b *strings.Builder, raw json.RawMessage) {
+	if raw == nil {
+		b.WriteString("null")
+		return
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		writeJSONString(b, string(raw))
+		return
+	}
+	b.Write(compact.Bytes())
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func FuzzWriteJSONString(f *testing.F) {
+	for _, s := range []string{"", "plain", `"quoted" \ back`, "line\nbreak\ttab\r", "\x00\x1f\x7f", "  ", "привет", "bad\xffutf8\xc3"} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		var b strings.Builder
+		writeJSONString(&b, s)
+
+		var got string
+		if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+			t.Fatalf("writeJSONString(%q) = %s: %v", s, b.String(), err)
+		}
+		// каждый некорректный байт становится U+FFFD
+		if want := validUTF8(s); got != want {
+			t.Fatalf("round trip of %q: got %q, want %q", s, got, want)
+		}
+	})
+}
+
+// validUTF8 заменяет каждый некорректный байт на U+FFFD
+func validUTF8(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package logger
+
+// badKey ключ для значения без пары или с нестроковым ключом, как в slog
+const badKey = "!BADKEY"
+
+// With возвращает дочерний логгер с полями из пар ключ-значение:
+//
+//	log.With("user", id, "attempt", n)
+//
+// Нестроковый ключ или значение без пары пишется под ключом !BADKEY.
+func (l *Logger) With(kv ...any) *Logger {
+	return l.WithFields(kvFields(kv))
+}
+
+// kvFields разбирает пары ключ-значение в поля
+func kvFields(kv []any) map[string]any {
+	fields := make(map[string]any, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i++ {
+		key, ok := kv[i].(string)
+		if !ok || i+1 == len(kv) {
+			fields[badKey] = kv[i]
+			continue
+		}
+		fields[key] = kv[i+1]
+		i++
+	}
+	return fields
+}
+
+// Debugkv пишет DEBUG с полями из пар ключ-значение, как в With
+func (l *Logger) Debugkv(msg string, kv ...any) {
+	if l.enabled(DEBUG) {
+		l.With(kv...).log(1, DEBUG, msg)
+	}
+}
+
+// Infokv пишет INFO с полями из пар ключ-значение:
+//
+//	log.Infokv("saved", "id", 42, "ms", 13)
+func (l *Logger) Infokv(msg string, kv ...any) {
+	if l.enabled(INFO) {
+		l.With(kv...).log(1, INFO, msg)
+	}
+}
+
+// Warnkv пишет WARN с полями из пар ключ-значение
+func (l *Logger) Warnkv(msg string, kv ...any) {
+	if l.enabled(WARN) {
+		l.With(kv...).log(1, WARN, msg)
+	}
+}
+
+// Errorkv пишет ERROR с полями из пар ключ-значение
+func (l *Logger) Errorkv(msg string, kv ...any) {
+	if l.enabled(ERROR) {
+		l.With(kv...).log(1, ERROR, msg)
+	}
+}
+
+// Fatalkv пишет FATAL с полями из пар ключ-значение и завершает процесс
+func (l *Logger) Fatalkv(msg string, kv ...any) {
+	l.With(kv...).log(1, FATAL, msg)
+	l.exit()
+}
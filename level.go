@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level тип для уровней логирования.
+// Между встроенными уровнями оставлены промежутки для пользовательских,
+// см. RegisterLevel.
+type Level int
+
+const (
+	DEBUG Level = 10 * iota
+	INFO
+	WARN
+	ERROR
+	FATAL
+)
+
+// Color codes для терминала
+const (
+	colorCyan    = "\033[36m"
+	colorGreen   = "\033[32m"
+	colorYellow  = "\033[33m"
+	colorRed     = "\033[31m"
+	colorMagenta = "\033[35m"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorDim    = "\033[2m"
+	colorNormal = "\033[22m" // обычная яркость без сброса цвета
+)
+
+// colorDefault цвет текста по умолчанию, остальные атрибуты сохраняются
+const colorDefault = "\033[39m"
+
+type levelInfo struct {
+	name  string
+	color string
+}
+
+// levelRegistry неизменяемый снимок зарегистрированных уровней
+type levelRegistry struct {
+	levels map[Level]levelInfo
+	width  int // длина самого длинного имени
+}
+
+var (
+	levelsMu sync.Mutex // сериализует RegisterLevel
+	levels   atomic.Pointer[levelRegistry]
+)
+
+func init() {
+	levels.Store(&levelRegistry{
+		levels: map[Level]levelInfo{
+			DEBUG: {"DEBUG", colorCyan},
+			INFO:  {"INFO", colorGreen},
+			WARN:  {"WARN", colorYellow},
+			ERROR: {"ERROR", colorRed},
+			FATAL: {"FATAL", colorMagenta},
+		},
+		width: len("DEBUG"),
+	})
+}
+
+// RegisterLevel регистрирует пользовательский уровень или переопределяет
+// имя и цвет существующего. Фильтрация идёт по числовому значению, поэтому,
+// например, RegisterLevel(WARN+5, "AUDIT", "\033[34m") ставит AUDIT между WARN и ERROR.
+// color — ANSI-последовательность для цветного вывода, может быть пустой.
+func RegisterLevel(value Level, name, color string) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	cur := levels.Load()
+	next := &levelRegistry{
+		levels: make(map[Level]levelInfo, len(cur.levels)+1),
+		width:  max(cur.width, len(name)),
+	}
+	for lv, info := range cur.levels {
+		next.levels[lv] = info
+	}
+	next.levels[value] = levelInfo{name: name, color: color}
+	levels.Store(next)
+}
+
+// String возвращает имя уровня; для неизвестных значений — LEVEL(n)
+func (lv Level) String() string {
+	if info, ok := levels.Load().levels[lv]; ok {
+		return info.name
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(lv))
+}
+
+// registered сообщает, известен ли уровень: встроенный или из RegisterLevel
+func (lv Level) registered() bool {
+	_, ok := levels.Load().levels[lv]
+	return ok
+}
+
+// color возвращает цвет уровня; для неизвестных значений — пустую строку
+func (lv Level) color() string {
+	return levels.Load().levels[lv].color
+}
+
+// levelWidth длина самого длинного имени зарегистрированного уровня
+func levelWidth() int {
+	return levels.Load().width
+}
+
+// ParseLevel разбирает имя уровня без учёта регистра, включая зарегистрированные
+// через RegisterLevel. Также принимаются WARNING и числовое значение уровня;
+// числа 1-4 старой нумерации отклоняются, если такой уровень не зарегистрирован.
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	if name == "WARNING" {
+		return WARN, nil
+	}
+
+	for lv, info := range levels.Load().levels {
+		if strings.ToUpper(info.name) == name {
+			return lv, nil
+		}
+	}
+
+	if n, err := strconv.Atoi(name); err == nil {
+		lv := Level(n)
+		// до перенумерации INFO был 1, WARN 2, ERROR 3, FATAL 4: такие числа
+		// из старых конфигов молча опустили бы порог до DEBUG
+		if n >= 1 && n <= 4 && !lv.registered() {
+			return 0, fmt.Errorf("logger: numeric level %d is from the old numbering, use %d (%s) or the level name", n, n*10, Level(n*10))
+		}
+		return lv, nil
+	}
+	return 0, fmt.Errorf("logger: unknown level %q", s)
+}
@@ -0,0 +1,18 @@
+package logger
+
+import "testing"
+
+func TestParseLevelRejectsOldNumbering(t *testing.T) {
+	for _, s := range []string{"1", "2", "3", "4"} {
+		if lv, err := ParseLevel(s); err == nil {
+			t.Errorf("ParseLevel(%q) = %v, want error", s, lv)
+		}
+	}
+
+	for s, want := range map[string]Level{"0": DEBUG, "10": INFO, "30": ERROR, "error": ERROR, "warning": WARN} {
+		lv, err := ParseLevel(s)
+		if err != nil || lv != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", s, lv, err, want)
+		}
+	}
+}
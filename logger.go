@@ -2,67 +2,86 @@ package logger
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"maps"
 	"os"
-	"runtime"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"gopkg.in/natefinch/lumberjack.v2"
-)
-
-// Level тип для уровней логирования
-type Level int
-
-const (
-	DEBUG Level = iota
-	INFO
-	WARN
-	ERROR
-	FATAL
 )
 
-var defaultLogger *Logger
-var once sync.Once
-
-var levelStrings = []string{
-	"DEBUG",
-	"INFO",
-	"WARN",
-	"ERROR",
-	"FATAL",
-}
-
-// Color codes для терминала
-var levelColors = []string{
-	"\033[36m", // DEBUG - cyan
-	"\033[32m", // INFO - green
-	"\033[33m", // WARN - yellow
-	"\033[31m", // ERROR - red
-	"\033[35m", // FATAL - magenta
-}
-
-const colorReset = "\033[0m"
-
 // Logger структура логгера
 type Logger struct {
-	mu         sync.Mutex
-	out        *log.Logger
-	level      Level
-	jsonOutput bool
-	showCaller bool
-	color      bool
-	fields     map[string]any
+	mu          *sync.Mutex // общий для логгеров с одним выходом
+	sink        *output
+	levelOut    map[Level]*output // выходы отдельных уровней, см. LevelFiles
+	level       *atomic.Int32     // общий с потомками, см. SetLevel
+	format      Format
+	showCaller  bool
+	color       bool
+	padLevel    bool
+	fieldSep    string
+	writerLvl   Level
+	fields      map[string]any
+	hooks       []Hook
+	collision   KeyCollision
+	merge       FieldMerge
+	stack       *atomic.Pointer[string]       // одноразовый стек из WithStack
+	middleware  *atomic.Pointer[[]Middleware] // цепочка Use, общая с дочерними логгерами
+	debugFields map[string]any                // поля только для DEBUG, см. WithDebugField
+	sighup      *sighupHandler                // nil, если HandleSIGHUP выключен
+	repeats     *repeats                      // nil, если CollapseRepeats выключен
+	timeout     time.Duration                 // WriteTimeout для WithWriter
+	now         func() time.Time
+	schedule    []LevelWindow
+	eol         string // окончание строки записи, см. LineTerminator
+	copyValues  bool
+	maxFields   int
+	noTime      bool
+	colorKeys   bool
+	showPackage bool
+	showSource  bool
+	ring        *ring
+	ctxExtract  func(ctx context.Context) map[string]any
+	fatal       *fatalHooks
+	cache       *fieldCache // сериализованные fields, см. cachedFields
+	audit       *output     // nil — события аудита идут в sink
+	quoteMsg    bool
+	onDrop      func(Entry)
+
+	replaceField   func(key string, value any) (string, any)
+	replaceBuiltin bool
+	ignoreStringer bool
+	timeFormat     string
+	cores          []*core
+	colorizer      func(key string, value any) (string, bool)
+	fail           FailBehavior
+	loc            *time.Location
+	pending        *debugBuffer // nil, если DebugOnError не включён
+	skipPkgs       []string
+	sampler        *sampler
+	prefix         string
+	onError        func(error)
+	fallback       bool
+	redact         []*regexp.Regexp
+	flatten        bool
+	eventID        bool
+	textJSON       bool
+	maxLine        int
+	fieldFuncs     []func() map[string]any
+	nilMode        NilRendering
+	reqHeaders     []string
 }
 
 // Config структура для настройки логгера
 type Config struct {
 	Level      Level
-	JsonOutput bool
+	JsonOutput bool   // устаревший флаг, то же что Format: FormatJSON
+	Format     Format // формат вывода, по умолчанию текст
 	ShowCaller bool
 	Color      bool
 	OutputFile string // если пустая строка — вывод в stdout
@@ -70,150 +89,862 @@ type Config struct {
 	MaxBackups int    // кол-во резервных файлов
 	MaxAgeDays int    // максимальный возраст файла в днях
 	Compress   bool   // сжимать старые файлы
+	Hooks      []Hook // получают каждую запись после вывода
+
+	// NoLevelPadding отключает выравнивание колонки уровня в текстовом формате
+	NoLevelPadding bool
+	// FieldSeparator отделяет сообщение от полей в текстовом формате, по умолчанию " | "
+	FieldSeparator string
+	// WriterLevel уровень записей, получаемых через Write; nil — INFO
+	WriterLevel *Level
+	// FlushInterval включает буферизацию файлового вывода и сброс буфера
+	// с этим периодом; буфер сбрасывается и при Close
+	FlushInterval time.Duration
+	// WriteBufferSize буферизует вывод (включая stdout) буфером этого размера
+	// в байтах, чтобы всплески записей не стоили системного вызова на строку.
+	// Сбрасывается при заполнении, Flush, Close, Fatal и раз в FlushInterval, если он задан.
+	WriteBufferSize int
+	// ReplaceField вызывается для каждого поля перед выводом и может переименовать,
+	// заменить или удалить его (пустой ключ). Подходит для маскирования и приведения типов.
+	ReplaceField func(key string, value any) (string, any)
+	// ReplaceBuiltin применяет ReplaceField и к встроенным ключам (time, level, message, caller)
+	ReplaceBuiltin bool
+	// KeyCollision политика для полей с ключами встроенных полей (time, level, ...)
+	KeyCollision KeyCollision
+	// FieldMerge политика для полей WithFields, чей ключ уже есть у родителя
+	FieldMerge FieldMerge
+	// MaxFields ограничивает число полей логгера; лишние поля отбрасываются,
+	// а их количество пишется в поле fields_dropped. 0 — без ограничений.
+	MaxFields int
+	// DisableTimestamp убирает время из записей, например если его уже
+	// добавляет среда выполнения контейнера или journald
+	DisableTimestamp bool
+	// ColorFieldKeys приглушает ключи полей в цветном текстовом выводе
+	ColorFieldKeys bool
+	// GzipStream сжимает вывод gzip на лету. Поток сбрасывается с периодом
+	// FlushInterval (по умолчанию раз в секунду) и завершается в Close.
+	// Не путать с Compress, который сжимает уже ротированные файлы; с ротацией
+	// по размеру (MaxSizeMB) не сочетается — ротация обрывает gzip-поток.
+	GzipStream bool
+	// ShowPackage добавляет поле pkg с путём пакета вызывающего кода
+	// (например github.com/acme/app/db) для маршрутизации по подсистемам
+	ShowPackage bool
+	// RingBufferSize хранит в памяти столько последних записей, см. Tail
+	RingBufferSize int
+	// ContextKeys ключи контекста, которые WithContext добавляет в поля;
+	// nil — DefaultContextKeys
+	ContextKeys []string
+	// ContextExtractor полностью заменяет чтение полей из контекста в WithContext
+	// и *Context-методах; ContextKeys при этом не используются
+	ContextExtractor func(ctx context.Context) map[string]any
+	// ExitFunc вызывается в Fatal после хуков OnFatal; по умолчанию os.Exit
+	ExitFunc func(code int)
+	// FatalTimeout сколько Fatal ждёт хуки OnFatal, по умолчанию 5s
+	FatalTimeout time.Duration
+	// Version и Commit добавляются в каждую запись полями version и git_commit,
+	// например из -ldflags "-X main.version=..."; пустые значения не пишутся
+	Version string
+	Commit  string
+	// Fields статические поля, которые есть у каждой записи (service, env и т.п.)
+	Fields map[string]any
+	// AuditFile файл для событий Audit (с теми же настройками ротации);
+	// AuditWriter — произвольный writer для них же. Если оба пустые,
+	// события аудита пишутся в основной вывод.
+	AuditFile   string
+	AuditWriter io.Writer
+	// QuoteMessage заключает в кавычки сообщение текстового формата, если в нём
+	// есть пробелы, '=' или кавычки (значения полей экранируются всегда)
+	QuoteMessage bool
+	// OnDrop вызывается для каждой потерянной записи (ошибка записи,
+	// отбрасывание сэмплером и т.п.), например для счётчика в метриках.
+	// Вызывается синхронно и не должен блокироваться.
+	OnDrop func(Entry)
+	// FileMode права файлов OutputFile и AuditFile, например 0640; 0 — по умолчанию (0600)
+	FileMode os.FileMode
+	// FileOwner и FileGroup владелец и группа файлов лога: имя или числовой id.
+	// Менять владельца обычно может только root; ошибка пишется в stderr.
+	FileOwner string
+	FileGroup string
+	// LevelFiles направляет записи отдельных уровней в свои файлы, например
+	// {ERROR: {Filename: "error.log"}, DEBUG: {Filename: "debug.log"}}.
+	// Уровни без файла пишутся в основной вывод.
+	LevelFiles map[Level]FileConfig
+	// HandleSIGHUP переоткрывает файлы лога по SIGHUP, как ожидает logrotate
+	// в режиме create. Обработчик снимается в Close.
+	HandleSIGHUP bool
+	// CollapseRepeats схлопывает подряд идущие одинаковые записи (уровень,
+	// сообщение и поля): повторы не выводятся, а перед следующей другой
+	// записью пишется последняя с полем repeated=N — числом пропущенных.
+	CollapseRepeats bool
+	// ShowSource добавляет к записям ERROR и выше поле source со строкой
+	// исходного кода в месте вызова. Без исходников на диске поле не пишется.
+	ShowSource bool
+	// Output писатель для вывода вместо stdout, например сетевое соединение;
+	// используется, если не задан OutputFile. Логгер его не закрывает.
+	Output io.Writer
+	// WriteTimeout ограничивает время одной записи в Output и писатели WithWriter.
+	// Не успевшая запись отбрасывается (с вызовом OnDrop), чтобы зависший
+	// сетевой выход не блокировал логирование. На файлы и stdout не влияет.
+	WriteTimeout time.Duration
+	// Now источник времени записей и расписания уровней, по умолчанию time.Now
+	Now func() time.Time
+	// LevelSchedule меняет порог уровня по времени суток, например чтобы
+	// DEBUG писался только ночью. Вне окон действует Level.
+	LevelSchedule []LevelWindow
+	// LineTerminator завершает каждую запись, по умолчанию "\n".
+	// Например "\r\n" для консолей Windows или "\x00" для разделения нулём.
+	LineTerminator string
+	// CopyFieldValues делает в WithFields глубокую копию срезов и map в значениях
+	// полей, чтобы их последующее изменение не меняло записи и не вызывало гонок.
+	// Стоит обхода через reflect и аллокаций на каждый вызов; по умолчанию
+	// значения не копируются.
+	CopyFieldValues bool
+	// JSONIgnoreStringer возвращает прежнее поведение: в JSON значения с методом
+	// String() сериализуются как есть (структура, число), а не строкой
+	JSONIgnoreStringer bool
+	// TimeFormat раскладка времени для текста и JSON (по умолчанию time.RFC3339),
+	// например TimeKitchenMillis. Формат GCP всегда использует RFC3339Nano.
+	TimeFormat string
+	// Cores дополнительные выводы со своим форматом, например цветной текст
+	// в stderr и JSON в файл для сборщика логов одновременно
+	Cores []Core
+	// FieldColorizer задаёт цвет значения поля в цветном текстовом выводе,
+	// например зелёный для status=ok и красный для status=fail. ansi —
+	// ANSI-последовательность цвета; ok == false оставляет цвет строки.
+	FieldColorizer func(key string, value any) (ansi string, ok bool)
+	// FailBehavior что делать при ошибке вывода (диск заполнен и т.п.):
+	// FailSilent (по умолчанию), FailStderr или FailPanic
+	FailBehavior FailBehavior
+	// Location часовой пояс времени записей и расписания уровней, например
+	// time.UTC. nil — как у источника времени (Now), обычно локальный.
+	Location *time.Location
+	// UTC время записей в UTC, короткая форма Location: time.UTC
+	UTC bool
+	// CallerSkipPackages пакеты, кадры которых пропускаются при поиске caller,
+	// например {"log", "net/http"} для ошибок http.Server через Write.
+	// Префикс совпадает с пакетом и его подпакетами. Если пропущены все
+	// кадры, caller указывает на первый из них.
+	CallerSkipPackages []string
+	// SampleRate сколько одинаковых записей (уровень и сообщение) в секунду
+	// пропускать; лишние отбрасываются. 0 — без сэмплирования.
+	SampleRate float64
+	// SampleBurst сколько одинаковых записей можно выдать подряд сверх
+	// SampleRate, по умолчанию SampleRate с округлением вверх
+	SampleBurst int
+	// SampleBelow уровень, начиная с которого записи не сэмплируются никогда,
+	// по умолчанию WARN: предупреждения и ошибки не теряются
+	SampleBelow Level
+	// SampleKey поле, по значению которого ведутся корзины SampleRate вместо
+	// уровня и сообщения, например "user_id": шумный пользователь
+	// ограничивается, остальные нет. Записи без поля сэмплируются как обычно.
+	SampleKey string
+	// SampleRates пропускает 1 из N записей уровня, например
+	// {INFO: 100, WARN: 10}. Уровни без записи и N <= 1 не сэмплируются.
+	// Не зависит от SampleBelow.
+	SampleRates map[Level]int
+	// Prefix метка в начале каждой текстовой строки, например "[worker-3]"
+	// для общего вывода нескольких процессов. В JSON пишется полем prefix.
+	Prefix string
+	// ErrorHandler получает ошибки вывода, в том числе неудачную ротацию
+	// файла (нет прав на каталог и т.п.). Вызывается синхронно.
+	ErrorHandler func(err error)
+	// FallbackToStdout пишет запись в stdout, если основной вывод вернул
+	// ошибку, чтобы сервис не замолкал. Запись при этом не считается
+	// потерянной: ошибка уходит только в ErrorHandler.
+	FallbackToStdout bool
+	// RedactPatterns заменяет на *** совпадения в сообщении и строковых
+	// значениях полей (и текстах ошибок), в том числе внутри []string, []any,
+	// map[string]string и map[string]any, например номера карт и bearer-токены.
+	// Дополняет ReplaceField, который работает по ключам.
+	RedactPatterns []*regexp.Regexp
+	// FlattenFields разворачивает в текстовом формате вложенные map в ключи
+	// через точку (user.id=1 user.name=bob), чтобы по ним удобно было искать grep
+	FlattenFields bool
+	// EventID добавляет к каждой записи поле event_id с коротким случайным ID,
+	// по которому строку из отчёта пользователя можно найти в логах
+	EventID bool
+	// NonBlockingStdout пишет в stdout из фоновой горутины через очередь.
+	// Если сборщик логов не успевает читать pipe и очередь заполнена, запись
+	// отбрасывается с вызовом OnDrop, а не блокирует приложение. Flush, Close
+	// и Fatal ждут вывода очереди не дольше секунды. По умолчанию stdout
+	// блокирующий, и записи не теряются.
+	NonBlockingStdout bool
+	// StdoutQueueSize размер очереди NonBlockingStdout, по умолчанию 1024 записи
+	StdoutQueueSize int
+	// TextJSONFields выводит поля текстовой записи компактным JSON-объектом
+	// вместо " | k=v": [INFO] ... сообщение {"user":1}. FieldSeparator,
+	// ColorFieldKeys и FieldColorizer при этом не применяются.
+	TextJSONFields bool
+	// MaxLineBytes ограничивает длину записи в байтах, 0 — без ограничения.
+	// Длинная запись обрезается (в JSON — за счёт строковых значений, чтобы
+	// остаться корректным JSON) и получает поле truncated=true.
+	// К формату FormatProto не применяется.
+	MaxLineBytes int
+	// NilRendering одинаковый вывод nil-значений полей (nil-интерфейсы,
+	// указатели, map, срезы и sql.Null* без значения) во всех форматах:
+	// NilString (<nil>), NilNull (null) или NilOmit (поле пропускается).
+	// По умолчанию <nil> в тексте и null в JSON. Значения внутри вложенных
+	// map выводятся как обычно.
+	NilRendering NilRendering
+	// EmitStartupLine пишет в New запись INFO "logger started" с действующими
+	// настройками: уровень, формат, вывод и ротация (поля logger_*)
+	EmitStartupLine bool
+	// Deferred создаёт логгер, который до вызова SetOutput копит записи
+	// в памяти (не больше DeferredBufferSize, по умолчанию 1 МБ; лишние
+	// отбрасываются с OnDrop), например пока конфигурация загружается.
+	// OutputFile, Output, GzipStream и буферизация основного вывода при этом
+	// не применяются. Если SetOutput так и не вызван, Close выводит
+	// накопленное в stderr.
+	Deferred bool
+	// DeferredBufferSize ёмкость буфера Deferred в байтах
+	DeferredBufferSize int
+	// RequestHeaders заголовки, которые LogRequest пишет в поле headers;
+	// по умолчанию заголовки не логируются
+	RequestHeaders []string
 }
 
 // New создаёт новый логгер по конфигу
 func New(cfg Config) *Logger {
-	var writer io.Writer
-
-	if cfg.OutputFile != "" {
-		writer = &lumberjack.Logger{
-			Filename:   cfg.OutputFile,
-			MaxSize:    cfg.MaxSizeMB,
-			MaxBackups: cfg.MaxBackups,
-			MaxAge:     cfg.MaxAgeDays,
-			Compress:   cfg.Compress,
-		}
+	var sink *output
+
+	if cfg.Deferred {
+		d := newDeferredBuffer(cfg.DeferredBufferSize)
+		sink = newOutput(d, d, outputOptions{})
+	} else if cfg.OutputFile != "" {
+		sink = newFileOutput(FileConfig{Filename: cfg.OutputFile}, cfg, outputOptions{
+			flushInterval: cfg.FlushInterval,
+			bufferSize:    cfg.WriteBufferSize,
+			gzip:          cfg.GzipStream,
+		})
+	} else if cfg.Output != nil {
+		sink = newOutput(withWriteTimeout(cfg.Output, cfg.WriteTimeout), nil, outputOptions{
+			flushInterval: cfg.FlushInterval,
+			bufferSize:    cfg.WriteBufferSize,
+			gzip:          cfg.GzipStream,
+			sync:          writerSyncer(cfg.Output),
+		})
 	} else {
-		writer = os.Stdout
+		opts := outputOptions{gzip: cfg.GzipStream}
+		if cfg.WriteBufferSize > 0 {
+			// stdout буферизуется только по явной просьбе
+			opts.bufferSize = cfg.WriteBufferSize
+			opts.flushInterval = cfg.FlushInterval
+		}
+		if cfg.NonBlockingStdout {
+			q := newQueueWriter(os.Stdout, cfg.StdoutQueueSize)
+			opts.drain = q.drain
+			sink = newOutput(q, q, opts)
+		} else {
+			sink = newOutput(os.Stdout, nil, opts)
+		}
 	}
 
-	return &Logger{
-		out:        log.New(writer, "", 0), // форматирование
-		level:      cfg.Level,
-		jsonOutput: cfg.JsonOutput,
-		showCaller: cfg.ShowCaller,
-		color:      cfg.Color,
+	format := cfg.Format
+	if cfg.JsonOutput && format == FormatText {
+		format = FormatJSON
 	}
-}
 
-func (l *Logger) log(level Level, msg string) {
-	if level < l.level {
-		return
+	fieldSep := cfg.FieldSeparator
+	if fieldSep == "" {
+		fieldSep = " | "
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	writerLvl := INFO
+	if cfg.WriterLevel != nil {
+		writerLvl = *cfg.WriterLevel
+	}
+
+	var audit *output
+	switch {
+	case cfg.AuditFile != "":
+		audit = newFileOutput(FileConfig{Filename: cfg.AuditFile}, cfg, outputOptions{})
+	case cfg.AuditWriter != nil:
+		audit = newOutput(cfg.AuditWriter, nil, outputOptions{sync: writerSyncer(cfg.AuditWriter)})
+	}
+
+	var levelOut map[Level]*output
+	for level, fc := range cfg.LevelFiles {
+		if levelOut == nil {
+			levelOut = make(map[Level]*output, len(cfg.LevelFiles))
+		}
+		levelOut[level] = newFileOutput(fc, cfg, outputOptions{
+			flushInterval: cfg.FlushInterval,
+			bufferSize:    cfg.WriteBufferSize,
+			gzip:          cfg.GzipStream,
+		})
+	}
+
+	timeFormat := cfg.TimeFormat
+	if timeFormat == "" {
+		timeFormat = time.RFC3339
+	}
+
+	eol := cfg.LineTerminator
+	if eol == "" {
+		eol = "\n"
+	}
 
-	now := time.Now().Format(time.RFC3339)
-	levelStr := levelStrings[level]
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+	loc := cfg.Location
+	if loc == nil && cfg.UTC {
+		loc = time.UTC
+	}
+	if loc != nil {
+		base := now
+		now = func() time.Time { return base().In(loc) }
+	}
 
-	entry := map[string]interface{}{
-		"time":    now,
-		"level":   levelStr,
-		"message": msg,
+	var rb *ring
+	if cfg.RingBufferSize > 0 {
+		rb = newRing(cfg.RingBufferSize)
 	}
 
-	if l.showCaller {
-		_, file, line, ok := runtime.Caller(3)
-		if ok {
-			shortFile := file[strings.LastIndex(file, "/")+1:]
-			entry["caller"] = fmt.Sprintf("%s:%d", shortFile, line)
+	ctxExtract := cfg.ContextExtractor
+	if ctxExtract == nil {
+		ctxKeys := cfg.ContextKeys
+		if ctxKeys == nil {
+			ctxKeys = DefaultContextKeys
 		}
+		ctxExtract = KeyExtractor(ctxKeys...)
 	}
 
-	for k, v := range l.fields {
-		entry[k] = v
+	fields := make(map[string]any, len(cfg.Fields)+2)
+	maps.Copy(fields, cfg.Fields)
+	if cfg.Version != "" {
+		fields["version"] = cfg.Version
+	}
+	if cfg.Commit != "" {
+		fields["git_commit"] = cfg.Commit
+	}
+
+	l := &Logger{
+		mu:          &sync.Mutex{},
+		sink:        sink,
+		levelOut:    levelOut,
+		level:       newLevelVar(cfg.Level),
+		format:      format,
+		showCaller:  cfg.ShowCaller,
+		color:       cfg.Color,
+		padLevel:    !cfg.NoLevelPadding,
+		fieldSep:    fieldSep,
+		writerLvl:   writerLvl,
+		hooks:       cfg.Hooks,
+		collision:   cfg.KeyCollision,
+		merge:       cfg.FieldMerge,
+		maxFields:   cfg.MaxFields,
+		noTime:      cfg.DisableTimestamp,
+		colorKeys:   cfg.ColorFieldKeys,
+		showPackage: cfg.ShowPackage,
+		showSource:  cfg.ShowSource,
+		timeout:     cfg.WriteTimeout,
+		now:         now,
+		schedule:    cfg.LevelSchedule,
+		eol:         eol,
+		copyValues:  cfg.CopyFieldValues,
+		ring:        rb,
+		ctxExtract:  ctxExtract,
+		fatal:       newFatalHooks(cfg.ExitFunc, cfg.FatalTimeout),
+		fields:      fields,
+		audit:       audit,
+		quoteMsg:    cfg.QuoteMessage,
+		onDrop:      cfg.OnDrop,
+		middleware:  &atomic.Pointer[[]Middleware]{},
+
+		replaceField:   cfg.ReplaceField,
+		replaceBuiltin: cfg.ReplaceBuiltin,
+		ignoreStringer: cfg.JSONIgnoreStringer,
+		timeFormat:     timeFormat,
+		colorizer:      cfg.FieldColorizer,
+		fail:           cfg.FailBehavior,
+		loc:            loc,
+		skipPkgs:       cfg.CallerSkipPackages,
+		prefix:         cfg.Prefix,
+		onError:        cfg.ErrorHandler,
+		fallback:       cfg.FallbackToStdout,
+		redact:         cfg.RedactPatterns,
+		flatten:        cfg.FlattenFields,
+		eventID:        cfg.EventID,
+		textJSON:       cfg.TextJSONFields,
+		maxLine:        cfg.MaxLineBytes,
+		nilMode:        cfg.NilRendering,
+		reqHeaders:     cfg.RequestHeaders,
+	}
+	l.cores = newCores(cfg)
+	l.cache = l.newCache()
+	if cfg.CollapseRepeats {
+		l.repeats = &repeats{}
+	}
+	l.sampler = newSampler(cfg)
+	if cfg.HandleSIGHUP {
+		l.sighup = startSIGHUP(l.Reopen)
+	}
+	if cfg.EmitStartupLine && l.enabled(INFO) {
+		l.WithFields(l.startupFields(cfg)).log(1, INFO, "logger started")
 	}
+	return l
+}
 
-	if l.jsonOutput {
-		data, _ := json.Marshal(entry)
-		l.out.Println(string(data))
+// log пишет запись; depth — число кадров стека между log и вызывающим кодом
+func (l *Logger) log(depth int, level Level, msg string) {
+	if !l.enabled(level) {
 		return
 	}
+	l.emit(l.newRecord(depth+1, level, msg, nil))
+}
+
+// record запись вместе с тем, что нужно для её вывода
+type record struct {
+	Entry
+	static *fieldCache    // кэш полей логгера
+	extra  map[string]any // поля, которые есть только у этой записи
 
-	// Текстовый лог
-	prefix := fmt.Sprintf("[%s] %s", levelStr, now)
-	if caller, ok := entry["caller"].(string); ok {
-		prefix += " " + caller
+	rawMessage bool // Message — готовый JSON, см. LogJSON
+}
+
+// newRecord собирает запись; extra — дополнительные поля только для неё
+func (l *Logger) newRecord(depth int, level Level, msg string, extra map[string]any) record {
+	static := l.cachedFields()
+	r := record{
+		Entry: Entry{
+			Time:    l.now(),
+			Level:   level,
+			Message: l.redactString(msg),
+			Fields:  static.fields,
+		},
+		static: static,
+		extra:  l.addDebugFields(level, l.addFuncFields(extra)),
 	}
 
-	line := prefix + " " + msg
-	if len(l.fields) > 0 {
-		var fieldStrs []string
-		for k, v := range l.fields {
-			fieldStrs = append(fieldStrs, fmt.Sprintf("%s=%v", k, v))
+	if st, ok := l.takeStack(); ok {
+		r.extra = withField(r.extra, "stacktrace", st)
+	}
+	if l.eventID {
+		r.extra = withField(r.extra, "event_id", newEventID())
+	}
+
+	if l.showCaller || l.showPackage || l.showSource {
+		frame, ok := callerFrame(depth+1, l.skipPkgs)
+		if ok && l.showCaller {
+			shortFile := frame.File[strings.LastIndex(frame.File, "/")+1:]
+			r.Caller = fmt.Sprintf("%s:%d", shortFile, frame.Line)
+		}
+		if ok && l.showPackage {
+			if pkg := packageOf(frame.Function); pkg != "" {
+				r.extra = withField(r.extra, "pkg", pkg)
+			}
+		}
+		if ok && l.showSource && level >= ERROR {
+			if src, found := sourceLine(frame.File, frame.Line); found {
+				r.extra = withField(r.extra, "source", src)
+			}
 		}
-		line += " | " + strings.Join(fieldStrs, " ")
 	}
 
-	if l.color {
-		color := levelColors[level]
-		l.out.Println(color + line + colorReset)
-	} else {
-		l.out.Println(line)
+	if r.extra != nil {
+		r.extra = l.prepareFields(r.extra)
+		r.Fields = make(map[string]any, len(static.fields)+len(r.extra))
+		maps.Copy(r.Fields, static.fields)
+		maps.Copy(r.Fields, r.extra)
 	}
+	return r
 }
 
-func (l *Logger) WithContext(ctx context.Context) *Logger {
-	fields := make(map[string]any)
+// emit выводит запись, сохраняет её в кольцевой буфер и передаёт хукам
+func (l *Logger) emit(r record) {
+	r, ok := l.applyMiddleware(r)
+	if !ok || l.sampled(r) || l.deferRecord(r) {
+		return
+	}
+	l.flushPending(r.Level)
+	if l.repeats != nil && l.collapse(r) {
+		return
+	}
+	l.output(r)
+}
 
-	if v := ctx.Value("request_id"); v != nil {
-		fields["request_id"] = v
+// output выводит запись и передаёт её буферу и хукам
+func (l *Logger) output(r record) {
+	err := l.write(r.Level, l.render(r))
+	if cerr := l.writeCores(r); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		l.drop(r.Entry, err)
 	}
-	if v := ctx.Value("user_id"); v != nil {
-		fields["user_id"] = v
+	if l.ring != nil {
+		l.ring.add(r.Entry)
 	}
+	l.fireHooks(r.Entry)
+}
 
-	return l.WithFields(fields)
+// Enabled сообщает, будет ли записан уровень level, с учётом порога WithLevel
+// и расписания уровней, например чтобы не собирать дорогие поля зря
+func (l *Logger) Enabled(level Level) bool {
+	return l.enabled(level)
 }
 
-// DefaultLogger возвращает лениво созданный логгер по умолчанию
-func DefaultLogger() *Logger {
-	once.Do(func() {
-		defaultLogger = New(Config{
-			Level:      INFO,
-			JsonOutput: false,
-			ShowCaller: true,
-			Color:      true,
-			OutputFile: "", // stdout
-		})
-	})
-	return defaultLogger
+// enabled сообщает, нужно ли собирать запись уровня level: она пройдёт
+// фильтр логгера или будет отложена DebugOnError
+func (l *Logger) enabled(level Level) bool {
+	return l.pending != nil || l.passes(level)
+}
+
+// passes сообщает, пройдёт ли запись уровня level фильтр логгера
+func (l *Logger) passes(level Level) bool {
+	if l.schedule != nil {
+		return level >= l.scheduledLevel()
+	}
+	return level >= Level(l.level.Load())
 }
 
+// write выводит готовую строку под мьютексом
+func (l *Logger) write(level Level, line string) error {
+	out := l.sink
+	if lo, ok := l.levelOut[level]; ok {
+		out = lo
+	}
+
+	data := []byte(line + l.terminator())
+
+	l.mu.Lock()
+	_, err := out.Write(data)
+	fallback := err != nil && l.fallback && !out.stdout()
+	if fallback {
+		_, ferr := os.Stdout.Write(data)
+		fallback = ferr == nil
+	}
+	l.mu.Unlock()
+
+	if fallback {
+		// запись не потеряна, но о сбое выхода нужно знать
+		l.handleError(err)
+		return nil
+	}
+	return err
+}
+
+// handleError передаёт ошибку вывода Config.ErrorHandler
+func (l *Logger) handleError(err error) {
+	if l.onError == nil {
+		return
+	}
+	defer func() { recover() }()
+	l.onError(err)
+}
+
+// reportError сообщает о проблеме самого логгера в stderr
+func reportError(err error) {
+	fmt.Fprintf(os.Stderr, "vira-logger: %v\n", err)
+}
+
+// drop сообщает ErrorHandler и OnDrop о записи, потерянной из-за ошибки err,
+// и реагирует согласно FailBehavior; паника в колбэках не выходит наружу
+func (l *Logger) drop(e Entry, err error) {
+	l.handleError(err)
+	l.notifyDrop(e)
+
+	switch l.fail {
+	case FailStderr:
+		reportError(fmt.Errorf("write failed, entry dropped: %w: [%s] %s", err, e.Level, e.Message))
+	case FailPanic:
+		panic(fmt.Sprintf("vira-logger: write failed: %v", err))
+	}
+}
+
+// notifyDrop вызывает OnDrop, перехватывая панику колбэка
+func (l *Logger) notifyDrop(e Entry) {
+	if l.onDrop == nil {
+		return
+	}
+	defer func() { recover() }()
+	l.onDrop(e)
+}
+
+// FailBehavior что делать, если запись не удалось вывести
+type FailBehavior int
+
+const (
+	FailSilent FailBehavior = iota // только OnDrop, по умолчанию
+	FailStderr                     // сообщение о потерянной записи в stderr
+	FailPanic                      // паника
+)
+
+// fireHooks передаёт запись хукам; ошибки хуков пишутся в stderr
+func (l *Logger) fireHooks(e Entry) {
+	for _, h := range l.hooks {
+		if err := h.Fire(e); err != nil {
+			reportError(fmt.Errorf("hook: %w", err))
+		}
+	}
+}
+
+// WithFields возвращает дочерний логгер с дополнительными полями.
+// Для пустого набора полей возвращается сам логгер без аллокаций.
 func (l *Logger) WithFields(fields map[string]any) *Logger {
-	newFields := make(map[string]any)
+	if len(fields) == 0 {
+		return l
+	}
+
+	newFields := make(map[string]any, len(l.fields)+len(fields))
 	for k, v := range l.fields {
 		newFields[k] = v
 	}
-	for k, v := range fields {
-		newFields[k] = v
+
+	if l.maxFields <= 0 || len(l.fields)+len(fields) <= l.maxFields {
+		for k, v := range fields {
+			l.mergeField(newFields, k, v)
+		}
+	} else {
+		// порядок обхода фиксирован, чтобы отбрасывались одни и те же поля
+		dropped := 0
+		for _, k := range slices.Sorted(maps.Keys(fields)) {
+			if _, exists := newFields[k]; !exists && fieldCount(newFields) >= l.maxFields {
+				dropped++
+				continue
+			}
+			l.mergeField(newFields, k, fields[k])
+		}
+		if dropped > 0 {
+			prev, _ := newFields[droppedFieldsKey].(int)
+			newFields[droppedFieldsKey] = prev + dropped
+		}
+	}
+
+	child := l.clone()
+	child.fields = newFields
+	child.cache = l.newCache()
+	return child
+}
+
+// FieldMerge что делать, если поле дочернего логгера уже есть у родителя
+type FieldMerge int
+
+const (
+	MergeOverwrite  FieldMerge = iota // новое значение заменяет старое, по умолчанию
+	MergeKeepParent                   // остаётся значение родителя
+	MergeAppend                       // значения собираются в []any
+)
+
+// mergeField добавляет поле в fields с учётом FieldMerge
+func (l *Logger) mergeField(fields map[string]any, key string, value any) {
+	if l.copyValues {
+		value = copyValue(value)
+	}
+
+	old, exists := fields[key]
+	switch {
+	case !exists || l.merge == MergeOverwrite:
+		fields[key] = value
+	case l.merge == MergeAppend:
+		if values, ok := old.([]any); ok {
+			// Clip, чтобы не писать в общий с родителем массив
+			fields[key] = append(slices.Clip(values), value)
+		} else {
+			fields[key] = []any{old, value}
+		}
 	}
+}
+
+// droppedFieldsKey поле-предупреждение с числом полей, отброшенных из-за MaxFields
+const droppedFieldsKey = "fields_dropped"
+
+// fieldCount число полей без учёта предупреждения о превышении MaxFields
+func fieldCount(fields map[string]any) int {
+	if _, ok := fields[droppedFieldsKey]; ok {
+		return len(fields) - 1
+	}
+	return len(fields)
+}
 
-	return &Logger{
-		out:        l.out,
-		level:      l.level,
-		jsonOutput: l.jsonOutput,
-		showCaller: l.showCaller,
-		color:      l.color,
-		fields:     newFields,
+// WithField возвращает дочерний логгер с одним дополнительным полем
+func (l *Logger) WithField(key string, value any) *Logger {
+	return l.WithFields(map[string]any{key: value})
+}
+
+// WithLevel возвращает дочерний логгер со своим порогом уровня, например
+// чтобы включить DEBUG для одного запроса, не меняя уровень родителя.
+// SetLevel родителя на такой логгер больше не влияет.
+func (l *Logger) WithLevel(level Level) *Logger {
+	child := l.clone()
+	child.level = newLevelVar(level)
+	child.schedule = nil // явный порог важнее расписания
+	return child
+}
+
+// SetLevel меняет порог уровня во время работы, например по команде
+// администратора. Изменение видят все потомки, кроме созданных через WithLevel
+// и их потомков. Окна LevelSchedule по-прежнему важнее.
+func (l *Logger) SetLevel(level Level) {
+	l.level.Store(int32(level))
+}
+
+// Level возвращает текущий порог уровня
+func (l *Logger) Level() Level {
+	return Level(l.level.Load())
+}
+
+func newLevelVar(level Level) *atomic.Int32 {
+	v := &atomic.Int32{}
+	v.Store(int32(level))
+	return v
+}
+
+// WithWriter возвращает дочерний логгер, который пишет в w,
+// сохраняя уровень, формат и поля родителя
+func (l *Logger) WithWriter(w io.Writer) *Logger {
+	child := l.clone()
+	child.mu = &sync.Mutex{}
+	child.sink = newOutput(withWriteTimeout(w, l.timeout), nil, outputOptions{sync: writerSyncer(w)})
+	child.levelOut = nil
+	if l.repeats != nil {
+		child.repeats = &repeats{}
 	}
+	return child
+}
+
+// Flush сбрасывает буферы вывода (WriteBufferSize, FlushInterval) без fsync
+func (l *Logger) Flush() error {
+	l.flushRepeats()
+	return l.eachOutput((*output).Flush)
+}
+
+// Sync сбрасывает буферы вывода и делает fsync файлов, чтобы записанное
+// пережило падение, например перед плановой остановкой. Для выходов без
+// буфера и синхронизации (stdout) ничего не делает и возвращает nil.
+func (l *Logger) Sync() error {
+	l.flushRepeats()
+	return l.eachOutput((*output).Sync)
+}
+
+// Close сбрасывает буферы и закрывает файл вывода.
+// Закрывает общий выход, поэтому вызывается один раз на корневом логгере.
+func (l *Logger) Close() error {
+	l.flushRepeats()
+	if l.sighup != nil {
+		l.sighup.stop()
+	}
+	return l.eachOutput((*output).Close)
+}
+
+// Reopen сбрасывает буферы и переоткрывает файлы лога, например после того,
+// как logrotate переименовал их. Выходы, не связанные с файлами, не меняются.
+func (l *Logger) Reopen() error {
+	return l.eachOutput((*output).Reopen)
+}
+
+// eachOutput применяет fn ко всем выходам логгера и возвращает первую ошибку
+func (l *Logger) eachOutput(fn func(*output) error) error {
+	err := fn(l.sink)
+	for _, o := range l.levelOut {
+		if oerr := fn(o); err == nil {
+			err = oerr
+		}
+	}
+	for _, c := range l.cores {
+		if cerr := fn(c.sink); err == nil {
+			err = cerr
+		}
+	}
+	if l.audit != nil {
+		if aerr := fn(l.audit); err == nil {
+			err = aerr
+		}
+	}
+	return err
+}
+
+// clone создаёт копию логгера; поля разделяются до первого изменения
+func (l *Logger) clone() *Logger {
+	child := *l
+	return &child
+}
+
+// Write реализует io.Writer: каждый вызов пишется как одна запись
+// уровня Config.WriterLevel без завершающего перевода строки
+func (l *Logger) Write(p []byte) (int, error) {
+	l.log(1, l.writerLvl, strings.TrimRight(string(p), "\r\n"))
+	return len(p), nil
 }
 
 func (l *Logger) Debug(format string, args ...interface{}) {
-	l.log(DEBUG, fmt.Sprintf(format, args...))
+	if l.enabled(DEBUG) {
+		l.log(1, DEBUG, fmt.Sprintf(format, args...))
+	}
 }
 func (l *Logger) Info(format string, args ...interface{}) {
-	l.log(INFO, fmt.Sprintf(format, args...))
+	if l.enabled(INFO) {
+		l.log(1, INFO, fmt.Sprintf(format, args...))
+	}
 }
 func (l *Logger) Warn(format string, args ...interface{}) {
-	l.log(WARN, fmt.Sprintf(format, args...))
+	if l.enabled(WARN) {
+		l.log(1, WARN, fmt.Sprintf(format, args...))
+	}
 }
 func (l *Logger) Error(format string, args ...interface{}) {
-	l.log(ERROR, fmt.Sprintf(format, args...))
+	if l.enabled(ERROR) {
+		l.log(1, ERROR, fmt.Sprintf(format, args...))
+	}
 }
 func (l *Logger) Fatal(format string, args ...interface{}) {
-	l.log(FATAL, fmt.Sprintf(format, args...))
-	os.Exit(1)
+	l.log(1, FATAL, fmt.Sprintf(format, args...))
+	l.exit()
+}
+
+// Logf пишет запись уровня, выбранного во время выполнения. Незарегистрированный
+// уровень пишется как ERROR с полем invalid_level. FATAL завершает процесс, как Fatal.
+func (l *Logger) Logf(level Level, format string, args ...interface{}) {
+	l.logf(2, level, format, args...)
+}
+
+func (l *Logger) logf(depth int, level Level, format string, args ...interface{}) {
+	target, level := l.validLevel(level)
+	if target.enabled(level) {
+		target.log(depth, level, fmt.Sprintf(format, args...))
+	}
+	if level == FATAL {
+		l.exit()
+	}
+}
+
+// LogDepth пишет готовое сообщение как Logf, но место вызова берётся на skip
+// кадров выше вызывающего LogDepth. Нужен обёрткам над логгером, например
+// адаптерам чужих API, чтобы в caller попадал их вызывающий код.
+func (l *Logger) LogDepth(skip int, level Level, msg string) {
+	target, level := l.validLevel(level)
+	if target.enabled(level) {
+		target.log(skip+1, level, msg)
+	}
+	if level == FATAL {
+		l.exit()
+	}
+}
+
+// LogAt пишет запись с заданным временем вместо текущего, например при
+// импорте или воспроизведении исторических событий и в тестах. Location
+// применяется и к t. Уровень выбирается как в Logf, но FATAL процесс
+// не завершает: запись относится к прошлому.
+func (l *Logger) LogAt(t time.Time, level Level, format string, args ...interface{}) {
+	target, level := l.validLevel(level)
+	if !target.enabled(level) {
+		return
+	}
+
+	r := target.newRecord(1, level, fmt.Sprintf(format, args...), nil)
+	r.Time = t
+	if l.loc != nil {
+		r.Time = t.In(l.loc)
+	}
+	target.emit(r)
+}
+
+// validLevel заменяет незарегистрированный уровень на ERROR с полем invalid_level
+func (l *Logger) validLevel(level Level) (*Logger, Level) {
+	if !level.registered() {
+		return l.WithField("invalid_level", int(level)), ERROR
+	}
+	return l, level
 }
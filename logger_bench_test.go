@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"io"
+	"testing"
+)
+
+func benchLogger(format Format) *Logger {
+	return New(Config{Output: io.Discard, Level: INFO, Format: format})
+}
+
+func TestFilteredDoesNotAllocate(t *testing.T) {
+	l := benchLogger(FormatJSON)
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		l.Debug("filtered %s", "message")
+	})
+	if allocs != 0 {
+		t.Fatalf("filtered Debug: want 0 allocs/op, got %v", allocs)
+	}
+}
+
+func BenchmarkFiltered(b *testing.B) {
+	l := benchLogger(FormatJSON)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("filtered %s", "message")
+	}
+}
+
+func BenchmarkJSON(b *testing.B) {
+	l := benchLogger(FormatJSON)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("request handled in %d ms", 42)
+	}
+}
+
+func BenchmarkText(b *testing.B) {
+	l := benchLogger(FormatText)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("request handled in %d ms", 42)
+	}
+}
+
+// BenchmarkWithFields новые поля в каждом вызове, без кэша
+func BenchmarkWithFields(b *testing.B) {
+	l := benchLogger(FormatJSON)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.WithFields(map[string]any{
+			"request_id": "abc123",
+			"user_id":    42,
+			"path":       "/api/v1/users",
+		}).Info("request handled")
+	}
+}
+
+// BenchmarkCachedFields поля логгера кодируются один раз и переиспользуются
+func BenchmarkCachedFields(b *testing.B) {
+	l := benchLogger(FormatJSON).WithFields(map[string]any{
+		"service":  "api",
+		"version":  "1.4.2",
+		"region":   "eu-west-1",
+		"instance": "i-0abc",
+		"pid":      4242,
+	})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("request handled")
+	}
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInvalidLevelDoesNotPanic(t *testing.T) {
+	var b strings.Builder
+	l := New(Config{Output: &b, Level: Level(-100), Color: true, DisableTimestamp: true, ExitFunc: func(int) {}})
+
+	for _, level := range []Level{Level(99), Level(-7), Level(1 << 20)} {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("log(%d) panicked: %v", level, r)
+				}
+			}()
+			l.log(1, level, "direct")
+			l.Logf(level, "via Logf %d", 1)
+			l.LogDepth(0, level, "via LogDepth")
+		}()
+	}
+
+	out := b.String()
+	if n := strings.Count(out, "\n"); n != 9 {
+		t.Fatalf("want 9 lines, got %d:\n%s", n, out)
+	}
+	if !strings.Contains(out, "invalid_level=99") {
+		t.Errorf("Logf(Level(99)) should be written with invalid_level, got:\n%s", out)
+	}
+	if strings.Contains(out, "panic") {
+		t.Errorf("unexpected panic text in output:\n%s", out)
+	}
+}
@@ -0,0 +1,333 @@
+// Package logpb кодирует и читает записи формата FormatProto: сообщения
+// LogEntry из logentry.proto, каждое с префиксом длины (varint).
+//
+// Кодирование написано вручную по схеме logentry.proto, а не сгенерировано
+// protoc, поэтому пакет не зависит от библиотек protobuf. Записи совместимы с любым
+// protobuf-декодером, сгенерированным из той же схемы.
+package logpb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+// Номера полей LogEntry
+const (
+	fieldTime       = 1
+	fieldLevel      = 2
+	fieldLevelValue = 3
+	fieldMessage    = 4
+	fieldCaller     = 5
+	fieldFields     = 6
+)
+
+// Номера полей Value
+const (
+	valueString = 1
+	valueInt    = 2
+	valueDouble = 3
+	valueBool   = 4
+	valueJSON   = 5
+)
+
+// Типы данных protobuf
+const (
+	wireVarint = 0
+	wireI64    = 1
+	wireLen    = 2
+	wireI32    = 5
+)
+
+// ErrTruncated возвращается, если запись обрывается на середине
+var ErrTruncated = errors.New("logpb: truncated record")
+
+// RawJSON значение поля, переданное как json_value
+type RawJSON []byte
+
+// LogEntry запись, как в logentry.proto. Значения Fields имеют тип string,
+// int64, float64, bool или RawJSON.
+type LogEntry struct {
+	TimeUnixNano int64
+	Level        string
+	LevelValue   int64
+	Message      string
+	Caller       string
+	Fields       map[string]any
+}
+
+// AppendHeader дописывает встроенные поля LogEntry; пустые значения пропускаются
+func AppendHeader(b []byte, e LogEntry) []byte {
+	if e.TimeUnixNano != 0 {
+		b = appendVarintField(b, fieldTime, uint64(e.TimeUnixNano))
+	}
+	b = appendStringField(b, fieldLevel, e.Level)
+	if e.LevelValue != 0 {
+		b = appendVarintField(b, fieldLevelValue, uint64(e.LevelValue))
+	}
+	b = appendStringField(b, fieldMessage, e.Message)
+	b = appendStringField(b, fieldCaller, e.Caller)
+	return b
+}
+
+// AppendField дописывает одну пару из fields. Пары можно кодировать
+// заранее и склеивать: в protobuf повторы map-поля просто дописываются.
+func AppendField(b []byte, key string, value any) []byte {
+	var entry []byte
+	entry = appendStringField(entry, 1, key)
+	entry = appendBytesField(entry, 2, appendValue(nil, value))
+	return appendBytesField(b, fieldFields, entry)
+}
+
+// AppendDelimited дописывает запись msg с префиксом длины
+func AppendDelimited(b, msg []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(msg)))
+	return append(b, msg...)
+}
+
+func appendValue(b []byte, v any) []byte {
+	switch val := v.(type) {
+	case string:
+		return appendStringFieldAlways(b, valueString, val)
+	case bool:
+		n := uint64(0)
+		if val {
+			n = 1
+		}
+		return appendVarintField(b, valueBool, n)
+	case int:
+		return appendVarintField(b, valueInt, uint64(val))
+	case int8:
+		return appendVarintField(b, valueInt, uint64(val))
+	case int16:
+		return appendVarintField(b, valueInt, uint64(val))
+	case int32:
+		return appendVarintField(b, valueInt, uint64(val))
+	case int64:
+		return appendVarintField(b, valueInt, uint64(val))
+	case uint8:
+		return appendVarintField(b, valueInt, uint64(val))
+	case uint16:
+		return appendVarintField(b, valueInt, uint64(val))
+	case uint32:
+		return appendVarintField(b, valueInt, uint64(val))
+	case uint:
+		if uint64(val) <= math.MaxInt64 {
+			return appendVarintField(b, valueInt, uint64(val))
+		}
+	case uint64:
+		if val <= math.MaxInt64 {
+			return appendVarintField(b, valueInt, val)
+		}
+	case float32:
+		return appendDoubleField(b, valueDouble, float64(val))
+	case float64:
+		return appendDoubleField(b, valueDouble, val)
+	case RawJSON:
+		return appendBytesField(b, valueJSON, val)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return appendStringFieldAlways(b, valueString, fmt.Sprintf("%v", v))
+	}
+	return appendBytesField(b, valueJSON, data)
+}
+
+func appendTag(b []byte, field, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = appendTag(b, field, wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendDoubleField(b []byte, field int, f float64) []byte {
+	b = appendTag(b, field, wireI64)
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+func appendStringField(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendStringFieldAlways(b, field, s)
+}
+
+// appendStringFieldAlways пишет и пустую строку: внутри oneof она значима.
+// Строки proto3 должны быть корректным UTF-8, иначе сгенерированные декодеры
+// отклоняют всю запись, поэтому некорректные байты заменяются на U+FFFD.
+func appendStringFieldAlways(b []byte, field int, s string) []byte {
+	s = validUTF8(s)
+	b = appendTag(b, field, wireLen)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// validUTF8 заменяет каждый некорректный байт на U+FFFD, как writeJSONString в логгере
+func validUTF8(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	b := make([]byte, 0, len(s)+8)
+	for _, r := range s {
+		b = utf8.AppendRune(b, r)
+	}
+	return string(b)
+}
+
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = appendTag(b, field, wireLen)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// Reader читает записи с префиксом длины
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader создаёт Reader поверх r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next читает следующую запись; в конце потока возвращает io.EOF
+func (r *Reader) Next() (*LogEntry, error) {
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrTruncated
+		}
+		return nil, err
+	}
+
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r.r, msg); err != nil {
+		return nil, ErrTruncated
+	}
+
+	e := &LogEntry{}
+	if err := e.Unmarshal(msg); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Unmarshal разбирает одну запись LogEntry без префикса длины.
+// Неизвестные поля пропускаются.
+func (e *LogEntry) Unmarshal(b []byte) error {
+	return walk(b, func(field, wire int, v uint64, data []byte) error {
+		switch {
+		case field == fieldTime && wire == wireVarint:
+			e.TimeUnixNano = int64(v)
+		case field == fieldLevel && wire == wireLen:
+			e.Level = string(data)
+		case field == fieldLevelValue && wire == wireVarint:
+			e.LevelValue = int64(v)
+		case field == fieldMessage && wire == wireLen:
+			e.Message = string(data)
+		case field == fieldCaller && wire == wireLen:
+			e.Caller = string(data)
+		case field == fieldFields && wire == wireLen:
+			key, value, err := unmarshalField(data)
+			if err != nil {
+				return err
+			}
+			if e.Fields == nil {
+				e.Fields = make(map[string]any)
+			}
+			e.Fields[key] = value
+		}
+		return nil
+	})
+}
+
+func unmarshalField(b []byte) (key string, value any, err error) {
+	err = walk(b, func(field, wire int, _ uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireLen:
+			key = string(data)
+		case field == 2 && wire == wireLen:
+			v, err := unmarshalValue(data)
+			if err != nil {
+				return err
+			}
+			value = v
+		}
+		return nil
+	})
+	return key, value, err
+}
+
+func unmarshalValue(b []byte) (value any, err error) {
+	err = walk(b, func(field, wire int, v uint64, data []byte) error {
+		switch {
+		case field == valueString && wire == wireLen:
+			value = string(data)
+		case field == valueInt && wire == wireVarint:
+			value = int64(v)
+		case field == valueDouble && wire == wireI64:
+			value = math.Float64frombits(v)
+		case field == valueBool && wire == wireVarint:
+			value = v != 0
+		case field == valueJSON && wire == wireLen:
+			value = RawJSON(append([]byte(nil), data...))
+		}
+		return nil
+	})
+	return value, err
+}
+
+// walk обходит поля сообщения. Для varint, i64 и i32 значение передаётся
+// в v, для длинных полей — в data.
+func walk(b []byte, fn func(field, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrTruncated
+		}
+		b = b[n:]
+		field, wire := int(tag>>3), int(tag&7)
+
+		var v uint64
+		var data []byte
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return ErrTruncated
+			}
+			b = b[n:]
+		case wireI64:
+			if len(b) < 8 {
+				return ErrTruncated
+			}
+			v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case wireI32:
+			if len(b) < 4 {
+				return ErrTruncated
+			}
+			v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case wireLen:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < size {
+				return ErrTruncated
+			}
+			data, b = b[n:n+int(size)], b[n+int(size):]
+		default:
+			return fmt.Errorf("logpb: unsupported wire type %d", wire)
+		}
+
+		if err := fn(field, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
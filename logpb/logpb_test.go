@@ -0,0 +1,57 @@
+package logpb
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	entries := []LogEntry{
+		{
+			TimeUnixNano: 1700000000123456789,
+			Level:        "ERROR",
+			LevelValue:   30,
+			Message:      "request failed",
+			Caller:       "main.go:42",
+			Fields: map[string]any{
+				"user":   "bob",
+				"n":      int64(-7),
+				"ratio":  0.25,
+				"ok":     false,
+				"empty":  "",
+				"nested": RawJSON(`{"a":[1,2]}`),
+			},
+		},
+		{Level: "DEBUG", Message: "bad\xffutf8", Fields: map[string]any{"k\xc3": "v\xfe"}},
+	}
+
+	var stream []byte
+	for _, e := range entries {
+		msg := AppendHeader(nil, e)
+		for k, v := range e.Fields {
+			msg = AppendField(msg, k, v)
+		}
+		stream = AppendDelimited(stream, msg)
+	}
+
+	want := []LogEntry{
+		entries[0],
+		{Level: "DEBUG", Message: "bad\uFFFDutf8", Fields: map[string]any{"k\uFFFD": "v\uFFFD"}},
+	}
+
+	r := NewReader(bytes.NewReader(stream))
+	for i, w := range want {
+		got, err := r.Next()
+		if err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(*got, w) {
+			t.Errorf("entry %d:\ngot  %#v\nwant %#v", i, *got, w)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("want io.EOF after the last entry, got %v", err)
+	}
+}
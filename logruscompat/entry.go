@@ -0,0 +1,211 @@
+// Package logruscompat повторяет API logrus (WithField, WithFields, WithError,
+// Infof и т.д.) поверх vira-logger, чтобы переводить код с logrus постепенно:
+//
+//	import logrus "github.com/skrolikov/vira-logger/logruscompat"
+//
+//	logrus.WithField("user", id).Infof("login from %s", ip)
+//
+// Пакет не зависит от logrus. Сигнатуры совпадают с logrus, но методы
+// возвращают *logruscompat.Entry, а не *logrus.Entry.
+package logruscompat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	logger "github.com/skrolikov/vira-logger"
+)
+
+// Fields поля записи, как logrus.Fields
+type Fields map[string]interface{}
+
+// panicLevel условный уровень Panic: запись идёт как ERROR, затем panic
+const panicLevel = logger.Level(-1)
+
+// Entry аналог logrus.Entry: логгер с накопленными полями
+type Entry struct {
+	l *logger.Logger
+}
+
+// NewEntry оборачивает логгер, как logrus.NewEntry
+func NewEntry(l *logger.Logger) *Entry {
+	return &Entry{l: l}
+}
+
+// Logger возвращает исходный логгер
+func (e *Entry) Logger() *logger.Logger {
+	return e.l
+}
+
+// WithField добавляет одно поле
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return &Entry{l: e.l.WithField(key, value)}
+}
+
+// WithFields добавляет несколько полей
+func (e *Entry) WithFields(fields Fields) *Entry {
+	return &Entry{l: e.l.WithFields(fields)}
+}
+
+// WithError добавляет ошибку, как logger.WithError
+func (e *Entry) WithError(err error) *Entry {
+	return &Entry{l: e.l.WithError(err)}
+}
+
+// WithContext добавляет поля из контекста, как logger.WithContext
+func (e *Entry) WithContext(ctx context.Context) *Entry {
+	return &Entry{l: e.l.WithContext(ctx)}
+}
+
+// log пишет сообщение; skip — кадры между вызывающим кодом и log
+func (e *Entry) log(skip int, level logger.Level, msg func() string) {
+	if level == panicLevel {
+		s := msg()
+		e.l.LogDepth(skip+1, logger.ERROR, s)
+		panic(s)
+	}
+	if e.l.Enabled(level) || level == logger.FATAL {
+		e.l.LogDepth(skip+1, level, msg())
+	}
+}
+
+// sprintln как fmt.Sprintln, но без перевода строки (как в logrus)
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// Trace пишет DEBUG: уровня TRACE в логгере нет
+func (e *Entry) Trace(args ...interface{}) {
+	e.log(1, logger.DEBUG, func() string { return fmt.Sprint(args...) })
+}
+
+// Tracef пишет DEBUG: уровня TRACE в логгере нет
+func (e *Entry) Tracef(format string, args ...interface{}) {
+	e.log(1, logger.DEBUG, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Traceln пишет DEBUG: уровня TRACE в логгере нет
+func (e *Entry) Traceln(args ...interface{}) {
+	e.log(1, logger.DEBUG, func() string { return sprintln(args...) })
+}
+
+// Debug пишет DEBUG
+func (e *Entry) Debug(args ...interface{}) {
+	e.log(1, logger.DEBUG, func() string { return fmt.Sprint(args...) })
+}
+
+// Debugf пишет DEBUG
+func (e *Entry) Debugf(format string, args ...interface{}) {
+	e.log(1, logger.DEBUG, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Debugln пишет DEBUG
+func (e *Entry) Debugln(args ...interface{}) {
+	e.log(1, logger.DEBUG, func() string { return sprintln(args...) })
+}
+
+// Info пишет INFO
+func (e *Entry) Info(args ...interface{}) {
+	e.log(1, logger.INFO, func() string { return fmt.Sprint(args...) })
+}
+
+// Infof пишет INFO
+func (e *Entry) Infof(format string, args ...interface{}) {
+	e.log(1, logger.INFO, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Infoln пишет INFO
+func (e *Entry) Infoln(args ...interface{}) {
+	e.log(1, logger.INFO, func() string { return sprintln(args...) })
+}
+
+// Print пишет INFO, как в logrus
+func (e *Entry) Print(args ...interface{}) {
+	e.log(1, logger.INFO, func() string { return fmt.Sprint(args...) })
+}
+
+// Printf пишет INFO, как в logrus
+func (e *Entry) Printf(format string, args ...interface{}) {
+	e.log(1, logger.INFO, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Println пишет INFO, как в logrus
+func (e *Entry) Println(args ...interface{}) {
+	e.log(1, logger.INFO, func() string { return sprintln(args...) })
+}
+
+// Warn пишет WARN
+func (e *Entry) Warn(args ...interface{}) {
+	e.log(1, logger.WARN, func() string { return fmt.Sprint(args...) })
+}
+
+// Warnf пишет WARN
+func (e *Entry) Warnf(format string, args ...interface{}) {
+	e.log(1, logger.WARN, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Warnln пишет WARN
+func (e *Entry) Warnln(args ...interface{}) {
+	e.log(1, logger.WARN, func() string { return sprintln(args...) })
+}
+
+// Warning то же, что Warn
+func (e *Entry) Warning(args ...interface{}) {
+	e.log(1, logger.WARN, func() string { return fmt.Sprint(args...) })
+}
+
+// Warningf то же, что Warn
+func (e *Entry) Warningf(format string, args ...interface{}) {
+	e.log(1, logger.WARN, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Warningln то же, что Warn
+func (e *Entry) Warningln(args ...interface{}) {
+	e.log(1, logger.WARN, func() string { return sprintln(args...) })
+}
+
+// Error пишет ERROR
+func (e *Entry) Error(args ...interface{}) {
+	e.log(1, logger.ERROR, func() string { return fmt.Sprint(args...) })
+}
+
+// Errorf пишет ERROR
+func (e *Entry) Errorf(format string, args ...interface{}) {
+	e.log(1, logger.ERROR, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Errorln пишет ERROR
+func (e *Entry) Errorln(args ...interface{}) {
+	e.log(1, logger.ERROR, func() string { return sprintln(args...) })
+}
+
+// Fatal пишет FATAL и завершает процесс
+func (e *Entry) Fatal(args ...interface{}) {
+	e.log(1, logger.FATAL, func() string { return fmt.Sprint(args...) })
+}
+
+// Fatalf пишет FATAL и завершает процесс
+func (e *Entry) Fatalf(format string, args ...interface{}) {
+	e.log(1, logger.FATAL, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Fatalln пишет FATAL и завершает процесс
+func (e *Entry) Fatalln(args ...interface{}) {
+	e.log(1, logger.FATAL, func() string { return sprintln(args...) })
+}
+
+// Panic пишет ERROR и вызывает panic с сообщением
+func (e *Entry) Panic(args ...interface{}) {
+	e.log(1, panicLevel, func() string { return fmt.Sprint(args...) })
+}
+
+// Panicf пишет ERROR и вызывает panic с сообщением
+func (e *Entry) Panicf(format string, args ...interface{}) {
+	e.log(1, panicLevel, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Panicln пишет ERROR и вызывает panic с сообщением
+func (e *Entry) Panicln(args ...interface{}) {
+	e.log(1, panicLevel, func() string { return sprintln(args...) })
+}
@@ -0,0 +1,170 @@
+package logruscompat
+
+import (
+	"context"
+	"fmt"
+
+	logger "github.com/skrolikov/vira-logger"
+)
+
+// Функции пакета пишут в логгер по умолчанию, как одноимённые функции logrus.
+
+// StandardEntry возвращает Entry над логгером по умолчанию
+func StandardEntry() *Entry {
+	return NewEntry(logger.DefaultLogger())
+}
+
+// WithField добавляет поле к логгеру по умолчанию
+func WithField(key string, value interface{}) *Entry {
+	return StandardEntry().WithField(key, value)
+}
+
+// WithFields добавляет поля к логгеру по умолчанию
+func WithFields(fields Fields) *Entry {
+	return StandardEntry().WithFields(fields)
+}
+
+// WithError добавляет ошибку к логгеру по умолчанию
+func WithError(err error) *Entry {
+	return StandardEntry().WithError(err)
+}
+
+// WithContext добавляет поля из контекста к логгеру по умолчанию
+func WithContext(ctx context.Context) *Entry {
+	return StandardEntry().WithContext(ctx)
+}
+
+// Trace пишет DEBUG: уровня TRACE в логгере нет
+func Trace(args ...interface{}) {
+	StandardEntry().log(1, logger.DEBUG, func() string { return fmt.Sprint(args...) })
+}
+
+// Tracef пишет DEBUG: уровня TRACE в логгере нет
+func Tracef(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.DEBUG, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Traceln пишет DEBUG: уровня TRACE в логгере нет
+func Traceln(args ...interface{}) {
+	StandardEntry().log(1, logger.DEBUG, func() string { return sprintln(args...) })
+}
+
+// Debug пишет DEBUG
+func Debug(args ...interface{}) {
+	StandardEntry().log(1, logger.DEBUG, func() string { return fmt.Sprint(args...) })
+}
+
+// Debugf пишет DEBUG
+func Debugf(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.DEBUG, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Debugln пишет DEBUG
+func Debugln(args ...interface{}) {
+	StandardEntry().log(1, logger.DEBUG, func() string { return sprintln(args...) })
+}
+
+// Info пишет INFO
+func Info(args ...interface{}) {
+	StandardEntry().log(1, logger.INFO, func() string { return fmt.Sprint(args...) })
+}
+
+// Infof пишет INFO
+func Infof(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.INFO, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Infoln пишет INFO
+func Infoln(args ...interface{}) {
+	StandardEntry().log(1, logger.INFO, func() string { return sprintln(args...) })
+}
+
+// Print пишет INFO, как в logrus
+func Print(args ...interface{}) {
+	StandardEntry().log(1, logger.INFO, func() string { return fmt.Sprint(args...) })
+}
+
+// Printf пишет INFO, как в logrus
+func Printf(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.INFO, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Println пишет INFO, как в logrus
+func Println(args ...interface{}) {
+	StandardEntry().log(1, logger.INFO, func() string { return sprintln(args...) })
+}
+
+// Warn пишет WARN
+func Warn(args ...interface{}) {
+	StandardEntry().log(1, logger.WARN, func() string { return fmt.Sprint(args...) })
+}
+
+// Warnf пишет WARN
+func Warnf(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.WARN, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Warnln пишет WARN
+func Warnln(args ...interface{}) {
+	StandardEntry().log(1, logger.WARN, func() string { return sprintln(args...) })
+}
+
+// Warning то же, что Warn
+func Warning(args ...interface{}) {
+	StandardEntry().log(1, logger.WARN, func() string { return fmt.Sprint(args...) })
+}
+
+// Warningf то же, что Warn
+func Warningf(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.WARN, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Warningln то же, что Warn
+func Warningln(args ...interface{}) {
+	StandardEntry().log(1, logger.WARN, func() string { return sprintln(args...) })
+}
+
+// Error пишет ERROR
+func Error(args ...interface{}) {
+	StandardEntry().log(1, logger.ERROR, func() string { return fmt.Sprint(args...) })
+}
+
+// Errorf пишет ERROR
+func Errorf(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.ERROR, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Errorln пишет ERROR
+func Errorln(args ...interface{}) {
+	StandardEntry().log(1, logger.ERROR, func() string { return sprintln(args...) })
+}
+
+// Fatal пишет FATAL и завершает процесс
+func Fatal(args ...interface{}) {
+	StandardEntry().log(1, logger.FATAL, func() string { return fmt.Sprint(args...) })
+}
+
+// Fatalf пишет FATAL и завершает процесс
+func Fatalf(format string, args ...interface{}) {
+	StandardEntry().log(1, logger.FATAL, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Fatalln пишет FATAL и завершает процесс
+func Fatalln(args ...interface{}) {
+	StandardEntry().log(1, logger.FATAL, func() string { return sprintln(args...) })
+}
+
+// Panic пишет ERROR и вызывает panic с сообщением
+func Panic(args ...interface{}) {
+	StandardEntry().log(1, panicLevel, func() string { return fmt.Sprint(args...) })
+}
+
+// Panicf пишет ERROR и вызывает panic с сообщением
+func Panicf(format string, args ...interface{}) {
+	StandardEntry().log(1, panicLevel, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Panicln пишет ERROR и вызывает panic с сообщением
+func Panicln(args ...interface{}) {
+	StandardEntry().log(1, panicLevel, func() string { return sprintln(args...) })
+}
@@ -0,0 +1,70 @@
+package logger
+
+import "maps"
+
+// Middleware обрабатывает запись перед выводом: может изменить сообщение,
+// уровень или поля. ok == false отбрасывает запись.
+type Middleware func(e Entry) (Entry, bool)
+
+// Use добавляет обработчики в конец цепочки. Цепочка общая для логгера
+// и всех логгеров, созданных от него через With*, и выполняется по порядку
+// для каждой записи до форматирования и хуков.
+func (l *Logger) Use(m ...Middleware) {
+	for {
+		old := l.middleware.Load()
+		var chain []Middleware
+		if old != nil {
+			chain = append(chain, *old...)
+		}
+		chain = append(chain, m...)
+		if l.middleware.CompareAndSwap(old, &chain) {
+			return
+		}
+	}
+}
+
+// applyMiddleware прогоняет запись через цепочку. Обработчики получают копию
+// полей, поэтому кэш полей логгера после цепочки не используется. Поля после
+// цепочки снова проходят подготовку, см. middlewareFields.
+func (l *Logger) applyMiddleware(r record) (record, bool) {
+	chain := l.middleware.Load()
+	if chain == nil {
+		return r, true
+	}
+
+	e := r.Entry
+	e.Fields = maps.Clone(e.Fields)
+	for _, m := range *chain {
+		var ok bool
+		if e, ok = m(e); !ok {
+			return r, false
+		}
+	}
+
+	e.Fields = l.middlewareFields(r.Fields, e.Fields)
+	r.Entry = e
+	r.static = &fieldCache{}
+	r.extra = e.Fields
+	return r, true
+}
+
+// middlewareFields готовит поля, вернувшиеся из цепочки: ReplaceField
+// применяется только к добавленным ключам (остальные его уже прошли),
+// NilOmit, RedactPatterns и KeyCollision — ко всем, иначе обработчик мог бы
+// вернуть, например, поле level и получить в JSON повторяющийся ключ
+func (l *Logger) middlewareFields(before, after map[string]any) map[string]any {
+	if l.replaceField != nil {
+		kept := make(map[string]any, len(after))
+		added := make(map[string]any)
+		for k, v := range after {
+			if _, ok := before[k]; ok {
+				kept[k] = v
+			} else {
+				added[k] = v
+			}
+		}
+		maps.Copy(kept, l.replaceFields(added))
+		after = kept
+	}
+	return l.resolveCollisions(l.redactFields(l.omitNil(after)))
+}
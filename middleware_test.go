@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareFieldsArePrepared(t *testing.T) {
+	var b strings.Builder
+	l := New(Config{Output: &b, Level: DEBUG, Format: FormatJSON, ReplaceField: func(k string, v any) (string, any) {
+		if k == "token" {
+			return k, "***"
+		}
+		return k, v
+	}})
+	l.Use(func(e Entry) (Entry, bool) {
+		e.Fields["level"] = "shadow"
+		e.Fields["token"] = "secret"
+		return e, true
+	})
+
+	l.Info("hello")
+
+	line := strings.TrimSpace(b.String())
+	if n := strings.Count(line, `"level":`); n != 1 {
+		t.Fatalf("want one level key, got %d: %s", n, line)
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["level"] != "INFO" || m["fields.level"] != "shadow" || m["token"] != "***" {
+		t.Fatalf("unexpected entry: %s", line)
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import "reflect"
+
+// NilRendering как выводить nil-значения полей
+type NilRendering int
+
+const (
+	NilDefault NilRendering = iota // <nil> в тексте и null в JSON, как раньше
+	NilString                      // <nil> во всех форматах (в JSON строкой)
+	NilNull                        // null во всех форматах
+	NilOmit                        // поле с nil не выводится
+)
+
+// isNil сообщает, что значение — nil: пустой интерфейс, nil-указатель,
+// map, срез, функция или канал
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// nilValue заменяет nil по NilRendering; ok == false — значение не nil
+// или действует поведение по умолчанию
+func (l *Logger) nilValue(v any, text bool) (any, bool) {
+	if l.nilMode == NilDefault || !isNil(v) {
+		return v, false
+	}
+	switch {
+	case l.nilMode == NilString || l.nilMode == NilOmit && text:
+		return "<nil>", true
+	case text:
+		return "null", true
+	}
+	return nil, true
+}
+
+// omitNil удаляет поля с nil-значениями, если выбран NilOmit. Значение
+// проверяется после приведения, поэтому пропускается и sql.NullInt64{}.
+func (l *Logger) omitNil(fields map[string]any) map[string]any {
+	if l.nilMode != NilOmit || len(fields) == 0 {
+		return fields
+	}
+
+	var out map[string]any
+	for k, v := range fields {
+		if !isNil(coerce(v)) {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(fields))
+			for k, v := range fields {
+				out[k] = v
+			}
+		}
+		delete(out, k)
+	}
+
+	if out == nil {
+		return fields
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type nilErr struct{}
+
+func (*nilErr) Error() string { panic("Error on nil receiver") }
+
+func nilFields() map[string]any {
+	var err error
+	var typedErr *nilErr
+	var ptr *int
+	var m map[string]int
+	return map[string]any{
+		"iface":    nil,
+		"err":      err,
+		"typed":    typedErr,
+		"ptr":      ptr,
+		"map":      m,
+		"nullint":  sql.NullInt64{},
+		"present":  1,
+		"validint": sql.NullInt64{Int64: 7, Valid: true},
+	}
+}
+
+func logNil(t *testing.T, format Format, mode NilRendering) string {
+	t.Helper()
+	var b strings.Builder
+	l := New(Config{
+		Output:           &b,
+		Format:           format,
+		DisableTimestamp: true,
+		NilRendering:     mode,
+		RedactPatterns:   []*regexp.Regexp{regexp.MustCompile(`secret`)},
+	})
+	l.WithFields(nilFields()).Info("nil")
+	return strings.TrimSpace(b.String())
+}
+
+func TestNilRenderingJSON(t *testing.T) {
+	nilKeys := []string{"iface", "err", "typed", "ptr", "map", "nullint"}
+	for mode, want := range map[NilRendering]any{NilDefault: nil, NilNull: nil, NilString: "<nil>"} {
+		line := logNil(t, FormatJSON, mode)
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("mode %d: invalid JSON %s: %v", mode, line, err)
+		}
+		for _, k := range nilKeys {
+			if v, ok := m[k]; !ok || v != want {
+				t.Errorf("mode %d: %s = %#v, want %#v: %s", mode, k, v, want, line)
+			}
+		}
+		if m["present"] != 1.0 || m["validint"] != 7.0 {
+			t.Errorf("mode %d: non-nil values changed: %s", mode, line)
+		}
+	}
+
+	line := logNil(t, FormatJSON, NilOmit)
+	for _, k := range nilKeys {
+		if strings.Contains(line, `"`+k+`"`) {
+			t.Errorf("NilOmit: %s must be omitted: %s", k, line)
+		}
+	}
+	if !strings.Contains(line, `"present":1`) || !strings.Contains(line, `"validint":7`) {
+		t.Errorf("NilOmit: non-nil values must be kept: %s", line)
+	}
+}
+
+func TestNilRenderingText(t *testing.T) {
+	for mode, want := range map[NilRendering]string{NilDefault: "<nil>", NilString: "<nil>", NilNull: "null"} {
+		line := logNil(t, FormatText, mode)
+		for _, k := range []string{"iface", "err", "typed", "ptr", "map", "nullint"} {
+			if mode == NilDefault && k == "map" {
+				continue // по умолчанию пустая map выводится как {}, как раньше
+			}
+			if !strings.Contains(line, k+"="+want) {
+				t.Errorf("mode %d: want %s=%s: %s", mode, k, want, line)
+			}
+		}
+	}
+
+	line := logNil(t, FormatText, NilOmit)
+	for _, k := range []string{"iface", "err", "typed", "ptr", "map", "nullint"} {
+		if strings.Contains(line, " "+k+"=") {
+			t.Errorf("NilOmit: %s must be omitted: %s", k, line)
+		}
+	}
+	if !strings.Contains(line, "present=1") || !strings.Contains(line, "validint=7") {
+		t.Errorf("NilOmit: non-nil values must be kept: %s", line)
+	}
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrQueueFull возвращается, если очередь неблокирующего stdout заполнена
+var ErrQueueFull = errors.New("logger: stdout queue full")
+
+// defaultStdoutQueueSize размер очереди NonBlockingStdout по умолчанию
+const defaultStdoutQueueSize = 1024
+
+// drainTimeout сколько Flush и Close ждут вывода очереди, если читатель stdout завис
+const drainTimeout = time.Second
+
+// queueWriter пишет в w из фоновой горутины. Если очередь заполнена
+// (сборщик логов не читает pipe), запись сразу отбрасывается с ErrQueueFull,
+// а не блокирует приложение.
+type queueWriter struct {
+	w     io.Writer
+	queue chan queued
+	stop  chan struct{}
+}
+
+// queued строка для вывода; если done != nil, он закрывается после вывода
+// строки (пустая строка — метка для drain)
+type queued struct {
+	line []byte
+	done chan struct{}
+}
+
+func newQueueWriter(w io.Writer, size int) *queueWriter {
+	if size <= 0 {
+		size = defaultStdoutQueueSize
+	}
+	q := &queueWriter{w: w, queue: make(chan queued, size), stop: make(chan struct{})}
+	go q.loop()
+	return q
+}
+
+func (q *queueWriter) Write(p []byte) (int, error) {
+	select {
+	case <-q.stop:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+
+	select {
+	case q.queue <- queued{line: bytes.Clone(p)}:
+		return len(p), nil
+	default:
+		return 0, ErrQueueFull
+	}
+}
+
+func (q *queueWriter) loop() {
+	for {
+		select {
+		case item := <-q.queue:
+			if item.line != nil {
+				q.w.Write(item.line)
+			}
+			if item.done != nil {
+				close(item.done)
+			}
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+// writeSync ставит p в очередь, даже если для этого нужно ждать места,
+// и возвращается после вывода: так пишутся события аудита, которые нельзя
+// отбросить. Порядок с обычными записями сохраняется.
+func (q *queueWriter) writeSync(p []byte) (int, error) {
+	done := make(chan struct{})
+	select {
+	case q.queue <- queued{line: bytes.Clone(p), done: done}:
+	case <-q.stop:
+		return 0, io.ErrClosedPipe
+	}
+
+	select {
+	case <-done:
+		return len(p), nil
+	case <-q.stop:
+		return 0, io.ErrClosedPipe
+	}
+}
+
+// drain ждёт, пока будут выведены строки, поставленные в очередь раньше,
+// но не дольше drainTimeout
+func (q *queueWriter) drain() error {
+	timer := time.NewTimer(drainTimeout)
+	defer timer.Stop()
+
+	done := make(chan struct{})
+	select {
+	case q.queue <- queued{done: done}:
+	case <-q.stop:
+		return nil
+	case <-timer.C:
+		return ErrWriteTimeout
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return ErrWriteTimeout
+	}
+}
+
+// Close останавливает фоновую горутину; сам w не закрывается.
+// Очередь выводится раньше, в output.Close через drain.
+func (q *queueWriter) Close() error {
+	close(q.stop)
+	return nil
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// gateWriter пишет в b, пока не закрыт open
+type gateWriter struct {
+	open chan struct{}
+	mu   sync.Mutex
+	b    strings.Builder
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	<-w.open
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func TestWriteSyncOnFullQueue(t *testing.T) {
+	gate := &gateWriter{open: make(chan struct{})}
+	q := newQueueWriter(gate, 1)
+	defer q.Close()
+	out := newOutput(q, nil, outputOptions{drain: q.drain})
+
+	// первая строка занимает горутину, вторая — очередь
+	out.Write([]byte("first\n"))
+	for {
+		if _, err := out.Write([]byte("second\n")); err == nil {
+			break
+		}
+	}
+	if _, err := out.Write([]byte("dropped\n")); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("want ErrQueueFull on a full queue, got %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := out.writeSync([]byte("audit\n"))
+		done <- err
+	}()
+	close(gate.open)
+	if err := <-done; err != nil {
+		t.Fatalf("writeSync: %v", err)
+	}
+
+	gate.mu.Lock()
+	got := gate.b.String()
+	gate.mu.Unlock()
+	if got != "first\nsecond\naudit\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
@@ -0,0 +1,345 @@
+// Package otlp отправляет записи vira-logger в OTLP-коллектор
+// по протоколу OTLP/HTTP с JSON-кодированием.
+//
+// Пакет использует только стандартную библиотеку, поэтому ядро логгера
+// не получает зависимостей от OpenTelemetry.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/skrolikov/vira-logger"
+)
+
+// ErrQueueFull возвращается Fire, если очередь экспорта переполнена
+var ErrQueueFull = errors.New("otlp: queue is full")
+
+// ErrClosed возвращается Fire после Close
+var ErrClosed = errors.New("otlp: exporter is closed")
+
+// Config настройки экспортёра
+type Config struct {
+	Endpoint      string            // полный адрес, например http://localhost:4318/v1/logs
+	ServiceName   string            // атрибут ресурса service.name
+	Headers       map[string]string // дополнительные HTTP-заголовки (авторизация и т.п.)
+	BatchSize     int               // макс. записей в одном запросе, по умолчанию 512
+	FlushInterval time.Duration     // период отправки неполного батча, по умолчанию 1s
+	QueueSize     int               // размер очереди, по умолчанию 4096
+	Client        *http.Client      // по умолчанию клиент с таймаутом 10s
+	ErrorHandler  func(error)       // по умолчанию ошибки пишутся в stderr
+}
+
+// Exporter реализует logger.Hook и отправляет записи батчами в фоне
+type Exporter struct {
+	cfg     Config
+	queue   chan logger.Entry
+	done    chan struct{}
+	wg      sync.WaitGroup
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// New создаёт экспортёр и запускает фоновую отправку
+func New(cfg Config) *Exporter {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 512
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 4096
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = func(err error) {
+			fmt.Fprintf(os.Stderr, "vira-logger: otlp: %v\n", err)
+		}
+	}
+
+	e := &Exporter{
+		cfg:   cfg,
+		queue: make(chan logger.Entry, cfg.QueueSize),
+		done:  make(chan struct{}),
+	}
+
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+// Fire ставит запись в очередь, не блокируясь
+func (e *Exporter) Fire(entry logger.Entry) error {
+	e.closeMu.RLock()
+	defer e.closeMu.RUnlock()
+
+	if e.closed {
+		return ErrClosed
+	}
+
+	select {
+	case e.queue <- entry:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close отправляет оставшиеся записи и останавливает экспортёр
+func (e *Exporter) Close() error {
+	e.closeMu.Lock()
+	if e.closed {
+		e.closeMu.Unlock()
+		return nil
+	}
+	e.closed = true
+	close(e.done)
+	e.closeMu.Unlock()
+
+	e.wg.Wait()
+	return nil
+}
+
+func (e *Exporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]logger.Entry, 0, e.cfg.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			e.cfg.ErrorHandler(err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry := <-e.queue:
+			batch = append(batch, entry)
+			if len(batch) >= e.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.done:
+			// Fire больше не пишет в очередь, дочитываем остаток
+			for {
+				select {
+				case entry := <-e.queue:
+					batch = append(batch, entry)
+					if len(batch) >= e.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *Exporter) send(batch []logger.Entry) error {
+	body, err := json.Marshal(e.request(batch))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, e.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp: collector responded %s", resp.Status)
+	}
+	return nil
+}
+
+// Схема OTLP/JSON: https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber"`
+	SeverityText         string     `json:"severityText"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"` // int64 кодируется строкой
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (e *Exporter) request(batch []logger.Entry) exportRequest {
+	observed := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	records := make([]logRecord, 0, len(batch))
+	for _, entry := range batch {
+		records = append(records, logRecord{
+			TimeUnixNano:         strconv.FormatInt(entry.Time.UnixNano(), 10),
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       SeverityNumber(entry.Level),
+			SeverityText:         entry.Level.String(),
+			Body:                 toAnyValue(entry.Message),
+			Attributes:           attributes(entry),
+		})
+	}
+
+	var res resource
+	if e.cfg.ServiceName != "" {
+		res.Attributes = []keyValue{{Key: "service.name", Value: toAnyValue(e.cfg.ServiceName)}}
+	}
+
+	return exportRequest{ResourceLogs: []resourceLogs{{
+		Resource: res,
+		ScopeLogs: []scopeLogs{{
+			Scope:      scope{Name: "github.com/skrolikov/vira-logger"},
+			LogRecords: records,
+		}},
+	}}}
+}
+
+// SeverityNumber переводит уровень логгера в номер серьёзности OTLP
+func SeverityNumber(level logger.Level) int {
+	switch {
+	case level >= logger.FATAL:
+		return 21
+	case level >= logger.ERROR:
+		return 17
+	case level >= logger.WARN:
+		return 13
+	case level >= logger.INFO:
+		return 9
+	default:
+		return 5
+	}
+}
+
+func attributes(entry logger.Entry) []keyValue {
+	attrs := make([]keyValue, 0, len(entry.Fields)+2)
+
+	if entry.Caller != "" {
+		file, line := entry.Caller, ""
+		if i := strings.LastIndex(entry.Caller, ":"); i >= 0 {
+			file, line = entry.Caller[:i], entry.Caller[i+1:]
+		}
+		attrs = append(attrs, keyValue{Key: "code.filepath", Value: toAnyValue(file)})
+		if n, err := strconv.ParseInt(line, 10, 64); err == nil {
+			attrs = append(attrs, keyValue{Key: "code.lineno", Value: toAnyValue(n)})
+		}
+	}
+
+	for k, v := range entry.Fields {
+		attrs = append(attrs, keyValue{Key: k, Value: toAnyValue(v)})
+	}
+	return attrs
+}
+
+func toAnyValue(v any) anyValue {
+	switch val := v.(type) {
+	case string:
+		return anyValue{StringValue: &val}
+	case bool:
+		return anyValue{BoolValue: &val}
+	case int:
+		return intValue(int64(val))
+	case int8:
+		return intValue(int64(val))
+	case int16:
+		return intValue(int64(val))
+	case int32:
+		return intValue(int64(val))
+	case int64:
+		return intValue(val)
+	case uint8:
+		return intValue(int64(val))
+	case uint16:
+		return intValue(int64(val))
+	case uint32:
+		return intValue(int64(val))
+	case float32:
+		return doubleValue(float64(val))
+	case float64:
+		return doubleValue(val)
+	case error:
+		s := val.Error()
+		return anyValue{StringValue: &s}
+	default:
+		s := fmt.Sprintf("%v", val)
+		return anyValue{StringValue: &s}
+	}
+}
+
+// doubleValue пишет NaN и ±Inf строкой: JSON их не допускает, и одно такое
+// значение провалило бы json.Marshal всего пакета
+func doubleValue(f float64) anyValue {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		s := strconv.FormatFloat(f, 'g', -1, 64)
+		return anyValue{StringValue: &s}
+	}
+	return anyValue{DoubleValue: &f}
+}
+
+func intValue(n int64) anyValue {
+	s := strconv.FormatInt(n, 10)
+	return anyValue{IntValue: &s}
+}
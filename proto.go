@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/skrolikov/vira-logger/logpb"
+)
+
+// formatProto кодирует запись в protobuf LogEntry с префиксом длины.
+// fields — уже закодированные пары, см. encodeProtoFields.
+func (l *Logger) formatProto(e Entry, fields string) string {
+	pe := logpb.LogEntry{LevelValue: int64(e.Level)}
+	if !l.noTime {
+		if _, v, ok := l.builtin("time", e.Time); ok {
+			if t, isTime := v.(interface{ UnixNano() int64 }); isTime {
+				pe.TimeUnixNano = t.UnixNano()
+			}
+		}
+	}
+	if _, v, ok := l.builtin("level", e.Level.String()); ok {
+		pe.Level = fmt.Sprint(v)
+	}
+	if _, v, ok := l.builtin("message", e.Message); ok {
+		pe.Message = fmt.Sprint(v)
+	}
+	if e.Caller != "" {
+		if _, v, ok := l.builtin("caller", e.Caller); ok {
+			pe.Caller = fmt.Sprint(v)
+		}
+	}
+
+	msg := logpb.AppendHeader(make([]byte, 0, 64+len(fields)), pe)
+	msg = append(msg, fields...)
+	return string(logpb.AppendDelimited(nil, msg))
+}
+
+// encodeProtoFields кодирует поля как повторы map-поля fields
+func (l *Logger) encodeProtoFields(keys []string, fields map[string]any) string {
+	var b []byte
+	for _, k := range keys {
+		v := l.jsonFieldValue(fields[k])
+		if raw, ok := v.(json.RawMessage); ok {
+			v = logpb.RawJSON(raw)
+		}
+		b = logpb.AppendField(b, k, v)
+	}
+	return string(b)
+}
+
+// terminator окончание записи: бинарные записи разделяет префикс длины
+func (l *Logger) terminator() string {
+	if l.format == FormatProto {
+		return ""
+	}
+	return l.eol
+}
@@ -0,0 +1,20 @@
+package logger
+
+import "encoding/json"
+
+// LogJSON пишет запись, сообщение которой — готовый JSON (например, из внешней
+// системы). В форматах JSON и GCP он встраивается в поле message как есть,
+// в текстовом выводится строкой. Некорректный JSON пишется строкой.
+//
+// Чтобы встроить готовый JSON в поле, передайте значение типа json.RawMessage.
+func (l *Logger) LogJSON(level Level, msg json.RawMessage) {
+	if !l.enabled(level) {
+		return
+	}
+	r := l.newRecord(1, level, string(msg), nil)
+	r.rawMessage = true
+	l.emit(r)
+	if level == FATAL {
+		l.exit()
+	}
+}
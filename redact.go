@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"maps"
+	"slices"
+)
+
+// redactMask замена совпадений RedactPatterns
+const redactMask = "***"
+
+// redactString маскирует в s совпадения RedactPatterns
+func (l *Logger) redactString(s string) string {
+	for _, re := range l.redact {
+		s = re.ReplaceAllString(s, redactMask)
+	}
+	return s
+}
+
+// redactFields применяет RedactPatterns к строковым значениям и ошибкам в полях,
+// в том числе внутри срезов и map (error_chain, заголовки запроса)
+func (l *Logger) redactFields(fields map[string]any) map[string]any {
+	if len(l.redact) == 0 || len(fields) == 0 {
+		return fields
+	}
+
+	var out map[string]any
+	for k, v := range fields {
+		if masked, ok := l.redactValue(v, 0); ok {
+			if out == nil {
+				out = maps.Clone(fields)
+			}
+			out[k] = masked
+		}
+	}
+
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// redactValue возвращает замаскированную копию v и true, если в нём было
+// что маскировать; исходное значение не меняется
+func (l *Logger) redactValue(v any, depth int) (any, bool) {
+	if depth >= maxStructDepth {
+		return v, false
+	}
+
+	switch val := v.(type) {
+	case string:
+		masked := l.redactString(val)
+		return masked, masked != val
+	case error:
+		if isNil(v) {
+			return v, false
+		}
+		s := val.Error()
+		masked := l.redactString(s)
+		return masked, masked != s
+	case []string:
+		var out []string
+		for i, s := range val {
+			if masked := l.redactString(s); masked != s {
+				if out == nil {
+					out = slices.Clone(val)
+				}
+				out[i] = masked
+			}
+		}
+		return out, out != nil
+	case []any:
+		var out []any
+		for i, item := range val {
+			if masked, ok := l.redactValue(item, depth+1); ok {
+				if out == nil {
+					out = slices.Clone(val)
+				}
+				out[i] = masked
+			}
+		}
+		return out, out != nil
+	case map[string]string:
+		var out map[string]string
+		for k, s := range val {
+			if masked := l.redactString(s); masked != s {
+				if out == nil {
+					out = maps.Clone(val)
+				}
+				out[k] = masked
+			}
+		}
+		return out, out != nil
+	case map[string]any:
+		var out map[string]any
+		for k, item := range val {
+			if masked, ok := l.redactValue(item, depth+1); ok {
+				if out == nil {
+					out = maps.Clone(val)
+				}
+				out[k] = masked
+			}
+		}
+		return out, out != nil
+	}
+	return v, false
+}
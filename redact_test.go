@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedactNestedValues(t *testing.T) {
+	var b strings.Builder
+	l := New(Config{
+		Output:           &b,
+		Format:           FormatJSON,
+		DisableTimestamp: true,
+		RedactPatterns:   []*regexp.Regexp{regexp.MustCompile(`secret-\w+`)},
+		RequestHeaders:   []string{"X-Api-Key"},
+	})
+
+	err := fmt.Errorf("call failed: %w", errors.New("token secret-abc rejected"))
+	l.WithError(err).Error("failed")
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Api-Key", "secret-def")
+	l.LogRequest(r)
+
+	nested := map[string]any{"list": []any{"secret-ghi", map[string]string{"k": "secret-jkl"}}}
+	l.WithField("nested", nested).Info("nested")
+
+	out := b.String()
+	if strings.Contains(out, "secret-") {
+		t.Fatalf("secret leaked:\n%s", out)
+	}
+	if n := strings.Count(out, "***"); n != 6 {
+		t.Fatalf("want 6 masked values, got %d:\n%s", n, out)
+	}
+	if nested["list"].([]any)[0] != "secret-ghi" {
+		t.Fatal("redaction must not modify the caller's values")
+	}
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// repeats схлопывает подряд идущие одинаковые записи (CollapseRepeats).
+// Хранит только последнюю запись и число её повторов.
+type repeats struct {
+	mu    sync.Mutex
+	key   string    // запись без времени в формате логгера
+	last  record    // последняя выведенная запись
+	count int       // сколько повторов пропущено
+	at    time.Time // время последнего повтора
+}
+
+// collapse возвращает true, если запись повторяет предыдущую и выводить её
+// не нужно. Перед новой, отличающейся записью выводит сводку о повторах.
+func (l *Logger) collapse(r record) bool {
+	keyRec := r
+	keyRec.Time = time.Time{}
+	key := l.render(keyRec)
+
+	rp := l.repeats
+	rp.mu.Lock()
+	if rp.key != "" && key == rp.key {
+		rp.count++
+		rp.at = r.Time
+		rp.mu.Unlock()
+		return true
+	}
+	summary, ok := rp.take()
+	rp.key, rp.last = key, r
+	rp.mu.Unlock()
+
+	if ok {
+		l.output(summary)
+	}
+	return false
+}
+
+// flushRepeats выводит сводку о пропущенных повторах, если они есть
+func (l *Logger) flushRepeats() {
+	if l.repeats == nil {
+		return
+	}
+
+	rp := l.repeats
+	rp.mu.Lock()
+	summary, ok := rp.take()
+	rp.key = ""
+	rp.mu.Unlock()
+
+	if ok {
+		l.output(summary)
+	}
+}
+
+// take возвращает последнюю запись с полем repeated=N и сбрасывает счётчик
+func (rp *repeats) take() (record, bool) {
+	if rp.count == 0 {
+		return record{}, false
+	}
+
+	r := rp.last
+	r.Time = rp.at
+	r.extra = withField(r.extra, "repeated", rp.count)
+	r.Fields = withField(r.Fields, "repeated", rp.count)
+	rp.count = 0
+	return r, true
+}
@@ -0,0 +1,58 @@
+package logger
+
+import "sync"
+
+// ring кольцевой буфер последних записей
+type ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int  // куда писать следующую запись
+	full    bool // буфер уже заполнялся целиком
+}
+
+func newRing(size int) *ring {
+	return &ring{entries: make([]Entry, size)}
+}
+
+func (r *ring) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// tail возвращает до n последних записей от старых к новым
+func (r *ring) tail(n int) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+
+	out := make([]Entry, n)
+	start := r.next - n
+	for i := range out {
+		out[i] = r.entries[(start+i+len(r.entries))%len(r.entries)]
+	}
+	return out
+}
+
+// Tail возвращает до n последних записей (n <= 0 — все) от старых к новым,
+// например для /debug-страницы или отчёта о падении. Требует Config.RingBufferSize > 0,
+// иначе возвращает nil. Буфер общий для логгера и его потомков.
+func (l *Logger) Tail(n int) []Entry {
+	if l.ring == nil {
+		return nil
+	}
+	return l.ring.tail(n)
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSampleKeys ограничивает число ключей сэмплера; при переполнении
+// вытесняются полностью восстановившиеся корзины
+const maxSampleKeys = 4096
+
+// sampler ограничивает поток одинаковых записей (уровень и сообщение
+// или значение поля SampleKey) корзиной токенов на каждый ключ и пропускает 1 из N записей уровня
+// по SampleRates. Корзина не применяется к записям уровня below и выше.
+type sampler struct {
+	mu      sync.Mutex
+	rate    float64 // токенов в секунду, 0 — без корзин
+	burst   float64 // ёмкость корзины
+	below   Level
+	buckets map[string]*bucket
+	field   string             // ключ корзины — значение этого поля, см. SampleKey
+	every   map[Level]*counter // не меняется после создания
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time // время последнего пополнения
+}
+
+// counter пропускает каждую n-ю запись уровня
+type counter struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+// newSampler создаёт сэмплер по конфигу; nil, если сэмплирование выключено
+func newSampler(cfg Config) *sampler {
+	if cfg.SampleRate <= 0 && len(cfg.SampleRates) == 0 {
+		return nil
+	}
+
+	s := &sampler{rate: cfg.SampleRate, field: cfg.SampleKey, buckets: make(map[string]*bucket)}
+	if s.rate > 0 {
+		burst := cfg.SampleBurst
+		if burst <= 0 {
+			burst = max(1, int(math.Ceil(s.rate)))
+		}
+		s.burst = float64(burst)
+		s.below = cfg.SampleBelow
+		if s.below == 0 {
+			s.below = WARN
+		}
+	}
+	for level, n := range cfg.SampleRates {
+		if n > 1 {
+			if s.every == nil {
+				s.every = make(map[Level]*counter, len(cfg.SampleRates))
+			}
+			s.every[level] = &counter{n: uint64(n)}
+		}
+	}
+	return s
+}
+
+// allow сообщает, пропустить ли запись
+func (s *sampler) allow(r record) bool {
+	if c, ok := s.every[r.Level]; ok && (c.count.Add(1)-1)%c.n != 0 {
+		return false
+	}
+	if s.rate <= 0 || r.Level >= s.below {
+		return true
+	}
+
+	key := r.Level.String() + "\x00" + r.Message
+	if v, ok := r.Fields[s.field]; s.field != "" && ok {
+		key = r.Level.String() + "\x01" + fmt.Sprint(v)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		if len(s.buckets) >= maxSampleKeys {
+			s.evict(r.Time)
+		}
+		b = &bucket{tokens: s.burst, at: r.Time}
+		s.buckets[key] = b
+	}
+
+	b.tokens = min(s.burst, b.tokens+r.Time.Sub(b.at).Seconds()*s.rate)
+	b.at = r.Time
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// evict удаляет корзины, которые к моменту now уже полны: их состояние
+// не отличается от новой. Если таких нет, сбрасывает все.
+func (s *sampler) evict(now time.Time) {
+	for k, b := range s.buckets {
+		if b.tokens+now.Sub(b.at).Seconds()*s.rate >= s.burst {
+			delete(s.buckets, k)
+		}
+	}
+	if len(s.buckets) >= maxSampleKeys {
+		clear(s.buckets)
+	}
+}
+
+// sampled сообщает, отброшена ли запись сэмплированием; об отброшенной
+// записи сообщается OnDrop
+func (l *Logger) sampled(r record) bool {
+	if l.sampler == nil || l.sampler.allow(r) {
+		return false
+	}
+	l.notifyDrop(r.Entry)
+	return true
+}
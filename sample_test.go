@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// lockedBuilder strings.Builder для конкурентной записи
+type lockedBuilder struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (w *lockedBuilder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *lockedBuilder) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.String()
+}
+
+func TestSampleRateKeepsWarnAndError(t *testing.T) {
+	var out lockedBuilder
+	l := New(Config{Output: &out, Level: DEBUG, DisableTimestamp: true, SampleRate: 10})
+
+	const workers, perWorker = 8, 500
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				l.Debug("flood")
+				l.Info("flood")
+				l.Warn("flood")
+				l.Error("flood")
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := out.String()
+	const total = workers * perWorker
+	if n := strings.Count(got, "[WARN]"); n != total {
+		t.Errorf("WARN: want all %d entries, got %d", total, n)
+	}
+	if n := strings.Count(got, "[ERROR]"); n != total {
+		t.Errorf("ERROR: want all %d entries, got %d", total, n)
+	}
+	for _, level := range []string{"[DEBUG]", "[INFO]"} {
+		if n := strings.Count(got, level); n == 0 || n >= total/2 {
+			t.Errorf("%s: want the flood sampled, got %d of %d", level, n, total)
+		}
+	}
+}
+
+func TestSampleRatesProportion(t *testing.T) {
+	var out strings.Builder
+	dropped := 0
+	l := New(Config{
+		Output:           &out,
+		DisableTimestamp: true,
+		SampleRates:      map[Level]int{INFO: 100},
+		OnDrop:           func(Entry) { dropped++ },
+	})
+
+	for i := 0; i < 10000; i++ {
+		l.Info("tick %d", i)
+		if i%10 == 0 {
+			l.Error("tock %d", i)
+		}
+	}
+
+	if n := strings.Count(out.String(), "[INFO]"); n != 100 {
+		t.Errorf("INFO: want 100 of 10000 entries, got %d", n)
+	}
+	if dropped != 9900 {
+		t.Errorf("OnDrop: want 9900 calls, got %d", dropped)
+	}
+	if n := strings.Count(out.String(), "[ERROR]"); n != 1000 {
+		t.Errorf("ERROR is not sampled: want 1000 entries, got %d", n)
+	}
+}
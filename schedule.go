@@ -0,0 +1,36 @@
+package logger
+
+import "time"
+
+// LevelWindow окно времени суток со своим минимальным уровнем, см. Config.LevelSchedule.
+// From и To — смещение от полуночи по часам Config.Now (в их часовом поясе),
+// конец не включается. Окно с From > To переходит через полночь.
+type LevelWindow struct {
+	From  time.Duration
+	To    time.Duration
+	Level Level
+}
+
+// contains сообщает, попадает ли время суток d в окно
+func (w LevelWindow) contains(d time.Duration) bool {
+	if w.From <= w.To {
+		return d >= w.From && d < w.To
+	}
+	return d >= w.From || d < w.To
+}
+
+// scheduledLevel порог уровня по расписанию на текущий момент: первое окно,
+// в которое попадает время, или Config.Level вне окон
+func (l *Logger) scheduledLevel() Level {
+	now := l.now()
+	h, m, s := now.Clock()
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second + time.Duration(now.Nanosecond())
+
+	for _, w := range l.schedule {
+		if w.contains(d) {
+			return w.Level
+		}
+	}
+	return Level(l.level.Load())
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// sighupHandler переоткрывает файлы лога по SIGHUP
+type sighupHandler struct {
+	sig  chan os.Signal
+	quit chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func startSIGHUP(reopen func() error) *sighupHandler {
+	h := &sighupHandler{
+		sig:  make(chan os.Signal, 1),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	signal.Notify(h.sig, syscall.SIGHUP)
+
+	go func() {
+		defer close(h.done)
+		for {
+			select {
+			case <-h.sig:
+				if err := reopen(); err != nil {
+					reportError(fmt.Errorf("reopen on SIGHUP: %w", err))
+				}
+			case <-h.quit:
+				return
+			}
+		}
+	}()
+	return h
+}
+
+// stop снимает обработчик сигнала; повторные вызовы ничего не делают
+func (h *sighupHandler) stop() {
+	h.once.Do(func() {
+		signal.Stop(h.sig)
+		close(h.quit)
+		<-h.done
+	})
+}
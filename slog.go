@@ -0,0 +1,40 @@
+package logger
+
+import "log/slog"
+
+// WithAttrs возвращает дочерний логгер с полями из атрибутов slog.
+// Группы становятся вложенными map[string]any, группа с пустым ключом
+// разворачивается на текущий уровень; LogValuer вычисляются сразу.
+func (l *Logger) WithAttrs(attrs ...slog.Attr) *Logger {
+	fields := make(map[string]any, len(attrs))
+	addAttrs(fields, attrs)
+	return l.WithFields(fields)
+}
+
+func addAttrs(fields map[string]any, attrs []slog.Attr) {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		// пустые атрибуты slog тоже пропускает
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+
+		if a.Value.Kind() != slog.KindGroup {
+			fields[a.Key] = a.Value.Any()
+			continue
+		}
+
+		group := a.Value.Group()
+		if len(group) == 0 {
+			continue
+		}
+		if a.Key == "" {
+			addAttrs(fields, group)
+			continue
+		}
+
+		nested := make(map[string]any, len(group))
+		addAttrs(nested, group)
+		fields[a.Key] = nested
+	}
+}
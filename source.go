@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sourceLines кэш прочитанных строк исходников по "файл:строка".
+// Пустая строка означает, что файл недоступен.
+var sourceLines sync.Map
+
+// sourceLine возвращает строку исходного кода в месте вызова. Если исходников
+// нет рядом с бинарником, ok == false и поле не добавляется.
+func sourceLine(file string, line int) (string, bool) {
+	key := file + ":" + strconv.Itoa(line)
+	if v, ok := sourceLines.Load(key); ok {
+		src := v.(string)
+		return src, src != ""
+	}
+
+	src := readLine(file, line)
+	sourceLines.Store(key, src)
+	return src, src != ""
+}
+
+func readLine(file string, line int) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if n == line {
+			return strings.TrimSpace(sc.Text())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// WithStack возвращает дочерний логгер, к следующей записи которого будет
+// приложен стек вызовов в поле stacktrace. Стек снимается в момент вызова
+// WithStack и прикладывается один раз — последующие записи идут без него.
+func (l *Logger) WithStack() *Logger {
+	st := captureStack(2)
+
+	child := l.clone()
+	child.stack = &atomic.Pointer[string]{}
+	child.stack.Store(&st)
+	return child
+}
+
+// captureStack форматирует стек, пропуская skip кадров (0 — сам captureStack)
+func captureStack(skip int) string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// callerFrame возвращает кадр вызывающего кода, как runtime.Caller(skip),
+// пропуская кадры пакетов из skipPkgs
+func callerFrame(skip int, skipPkgs []string) (runtime.Frame, bool) {
+	pcs := make([]uintptr, 1)
+	if len(skipPkgs) > 0 {
+		pcs = make([]uintptr, 32)
+	}
+	n := runtime.Callers(skip+2, pcs)
+	if n == 0 {
+		return runtime.Frame{}, false
+	}
+
+	frames := runtime.CallersFrames(pcs[:n])
+	first, more := frames.Next()
+	for frame := first; ; frame, more = frames.Next() {
+		if !skipPackage(packageOf(frame.Function), skipPkgs) {
+			return frame, true
+		}
+		if !more {
+			return first, true
+		}
+	}
+}
+
+// skipPackage сообщает, совпадает ли pkg с одним из префиксов или вложен в него
+func skipPackage(pkg string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if pkg == p || strings.HasPrefix(pkg, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// packageOf возвращает путь пакета функции: из
+// github.com/acme/app/db.(*Store).Get получится github.com/acme/app/db
+func packageOf(funcName string) string {
+	slash := strings.LastIndex(funcName, "/")
+	dot := strings.Index(funcName[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return funcName[:slash+1+dot]
+}
+
+// takeStack забирает одноразовый стек, если он есть
+func (l *Logger) takeStack() (string, bool) {
+	if l.stack == nil {
+		return "", false
+	}
+	st := l.stack.Swap(nil)
+	if st == nil {
+		return "", false
+	}
+	return *st, true
+}
+
+// withField возвращает копию fields с добавленным полем
+func withField(fields map[string]any, key string, value any) map[string]any {
+	out := make(map[string]any, len(fields)+1)
+	for k, v := range fields {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
@@ -0,0 +1,33 @@
+package logger
+
+// startupFields действующие настройки логгера для EmitStartupLine
+func (l *Logger) startupFields(cfg Config) map[string]any {
+	fields := map[string]any{
+		"logger_level":  l.Level().String(),
+		"logger_format": l.format.String(),
+	}
+
+	switch {
+	case cfg.OutputFile != "":
+		fields["logger_output"] = cfg.OutputFile
+		fields["logger_max_size_mb"] = cfg.MaxSizeMB
+		fields["logger_max_backups"] = cfg.MaxBackups
+		fields["logger_max_age_days"] = cfg.MaxAgeDays
+		fields["logger_compress"] = cfg.Compress
+	case cfg.Output != nil:
+		fields["logger_output"] = "writer"
+	default:
+		fields["logger_output"] = "stdout"
+	}
+	if len(cfg.LevelFiles) > 0 {
+		files := make(map[string]string, len(cfg.LevelFiles))
+		for level, fc := range cfg.LevelFiles {
+			files[level.String()] = fc.Filename
+		}
+		fields["logger_level_files"] = files
+	}
+	if len(cfg.Cores) > 0 {
+		fields["logger_cores"] = len(cfg.Cores)
+	}
+	return fields
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// maxStructDepth ограничивает вложенность WithStruct (защита от циклов через указатели)
+const maxStructDepth = 8
+
+// WithStruct возвращает дочерний логгер с полями из экспортируемых полей структуры v.
+// Имя поля задаётся тегом `log:"user_id"`, `log:"-"` пропускает поле,
+// `log:"name,omitempty"` пропускает нулевые значения. Вложенные структуры
+// разворачиваются в ключи через точку (address.city), встроенные — без префикса.
+// nil-указатели на структуры записываются как nil; не-структуры игнорируются.
+func (l *Logger) WithStruct(v any) *Logger {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return l
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return l
+	}
+
+	fields := make(map[string]any)
+	flattenStruct(fields, "", rv, 0)
+	return l.WithFields(fields)
+}
+
+// FieldProvider тип, который сам описывает свои поля в логе, см. WithObject
+type FieldProvider interface {
+	LogFields() map[string]any
+}
+
+// WithObject возвращает дочерний логгер с полями, которые задаёт сам объект,
+// например User или Order. nil и nil-указатели игнорируются.
+func (l *Logger) WithObject(o FieldProvider) *Logger {
+	if o == nil {
+		return l
+	}
+	if rv := reflect.ValueOf(o); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return l
+	}
+	return l.WithFields(o.LogFields())
+}
+
+func flattenStruct(fields map[string]any, prefix string, rv reflect.Value, depth int) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := parseLogTag(sf)
+		if skip {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		key := name
+		if sf.Anonymous && sf.Tag.Get("log") == "" {
+			key = "" // поля встроенной структуры поднимаются на уровень выше
+		}
+		if prefix != "" && key != "" {
+			key = prefix + "." + key
+		} else if key == "" {
+			key = prefix
+		}
+
+		nested, isNil := structValue(fv)
+		switch {
+		case isNil:
+			if key != "" {
+				fields[key] = nil
+			}
+		case nested.IsValid() && depth < maxStructDepth:
+			flattenStruct(fields, key, nested, depth+1)
+		default:
+			if key != "" {
+				fields[key] = fv.Interface()
+			}
+		}
+	}
+}
+
+// parseLogTag разбирает тег log; имя по умолчанию — имя поля
+func parseLogTag(sf reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := sf.Tag.Get("log")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, opts == "omitempty", false
+}
+
+// structValue возвращает структуру для разворачивания, если fv — структура
+// или указатель на неё и тип не умеет сам себя форматировать (time.Time и т.п.)
+func structValue(fv reflect.Value) (nested reflect.Value, isNil bool) {
+	if fv.Kind() == reflect.Pointer {
+		if fv.Type().Elem().Kind() != reflect.Struct || selfFormatting(fv) {
+			return reflect.Value{}, false
+		}
+		if fv.IsNil() {
+			return reflect.Value{}, true
+		}
+		fv = fv.Elem()
+	}
+	if fv.Kind() != reflect.Struct || selfFormatting(fv) {
+		return reflect.Value{}, false
+	}
+	return fv, false
+}
+
+func selfFormatting(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	switch v.Interface().(type) {
+	case fmt.Stringer, error, json.Marshaler, encoding.TextMarshaler:
+		return true
+	}
+	if v.Kind() != reflect.Pointer && reflect.PointerTo(v.Type()).Implements(reflect.TypeFor[fmt.Stringer]()) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrWriteTimeout возвращается, если запись в выход не завершилась за WriteTimeout
+var ErrWriteTimeout = errors.New("logger: write timed out")
+
+// timeoutWriter ограничивает время записи в медленный (сетевой) выход.
+// Зависшая запись бросается: логгер получает ErrWriteTimeout и отбрасывает
+// запись, а не держит мьютекс. Следующие записи ждут завершения зависшей
+// не дольше того же таймаута.
+type timeoutWriter struct {
+	w       io.Writer
+	timeout time.Duration
+	busy    chan struct{} // занят, пока идёт запись
+}
+
+// withWriteTimeout оборачивает w, если задан таймаут. Файлы, stdout и stderr
+// остаются как есть.
+func withWriteTimeout(w io.Writer, timeout time.Duration) io.Writer {
+	if _, ok := w.(*os.File); ok || timeout <= 0 {
+		return w
+	}
+	return &timeoutWriter{w: w, timeout: timeout, busy: make(chan struct{}, 1)}
+}
+
+func (t *timeoutWriter) Write(p []byte) (int, error) {
+	timer := time.NewTimer(t.timeout)
+	defer timer.Stop()
+
+	select {
+	case t.busy <- struct{}{}:
+	case <-timer.C:
+		return 0, ErrWriteTimeout
+	}
+
+	// p может быть переиспользован после возврата, а запись — ещё идти
+	buf := bytes.Clone(p)
+	done := make(chan error, 1)
+	go func() {
+		defer func() { <-t.busy }()
+		_, err := t.w.Write(buf)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	case <-timer.C:
+		return 0, ErrWriteTimeout
+	}
+}
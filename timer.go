@@ -0,0 +1,46 @@
+package logger
+
+import "time"
+
+// Timer засекает время и возвращает функцию, которая пишет INFO
+// "operation finished" с полями op=name и duration_ms:
+//
+//	defer log.Timer("load_config")()
+func (l *Logger) Timer(name string) func() {
+	start := time.Now()
+	return func() {
+		if !l.enabled(INFO) {
+			return
+		}
+		l.WithFields(map[string]any{
+			"op":          name,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}).log(1, INFO, "operation finished")
+	}
+}
+
+// LatencyBucket относит длительность к корзине для SLO-отчётов:
+// "<10ms", "<100ms", "<1s" или ">=1s"
+func LatencyBucket(d time.Duration) string {
+	switch {
+	case d < 10*time.Millisecond:
+		return "<10ms"
+	case d < 100*time.Millisecond:
+		return "<100ms"
+	case d < time.Second:
+		return "<1s"
+	default:
+		return ">=1s"
+	}
+}
+
+// WithLatency возвращает дочерний логгер с полями duration_ms и latency_bucket,
+// чтобы все дашборды по логам считали корзины одинаково:
+//
+//	log.WithLatency(time.Since(start)).Info("query done")
+func (l *Logger) WithLatency(d time.Duration) *Logger {
+	return l.WithFields(map[string]any{
+		"duration_ms":    d.Milliseconds(),
+		"latency_bucket": LatencyBucket(d),
+	})
+}
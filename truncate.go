@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"maps"
+	"unicode/utf8"
+)
+
+// truncatedMarker поле-признак обрезанной записи, см. MaxLineBytes
+const truncatedMarker = "truncated"
+
+// truncatedValue замена нестрокового значения, которое не уместилось в MaxLineBytes
+const truncatedValue = "<truncated>"
+
+// maxTruncateSteps ограничивает число попыток ужать JSON-запись
+const maxTruncateSteps = 32
+
+// truncate укладывает запись в MaxLineBytes. Текст обрезается по границе
+// символа UTF-8; в JSON укорачиваются самые длинные строки (сообщение
+// и значения полей), а слишком большие нестроковые значения заменяются
+// на "<truncated>", чтобы запись осталась корректным JSON.
+func (l *Logger) truncate(r record, line string) string {
+	if l.format == FormatText {
+		marker := " " + truncatedMarker + "=true"
+		if l.color {
+			marker += colorReset
+		}
+		return cutUTF8(line, l.maxLine-len(marker)) + marker
+	}
+
+	fields := make(map[string]any, len(r.Fields)+1)
+	for k, v := range r.Fields {
+		fields[k] = fieldValue(v)
+	}
+	fields[truncatedMarker] = true
+	r.rawMessage = false
+
+	for range maxTruncateSteps {
+		line = l.renderTruncated(r, fields)
+		over := len(line) - l.maxLine
+		if over <= 0 {
+			return line
+		}
+
+		// укорачиваем самую длинную строку; вырезанный байт убирает из
+		// вывода не меньше байта, поэтому шаги быстро сходятся
+		key, longest, total := "", len(r.Message), len(r.Message)
+		for k, v := range fields {
+			if s, ok := v.(string); ok {
+				total += len(s)
+				if len(s) > longest {
+					key, longest = k, len(s)
+				}
+			}
+		}
+
+		// нестроковое значение (срез, map, структура) укоротить нельзя,
+		// его можно только заменить. Заменяем его до того, как резать строки,
+		// если оно больше самой длинной строки или строки всё равно не вместят
+		// превышение: иначе строки были бы урезаны и за его счёт
+		big, size := l.largestNonString(fields)
+		if size > len(truncatedValue)+2 && (size > longest || total < over) {
+			fields[big] = truncatedValue
+			continue
+		}
+
+		if longest == 0 {
+			break
+		}
+		if key == "" {
+			r.Message = cutUTF8(r.Message, len(r.Message)-over)
+		} else {
+			s := fields[key].(string)
+			fields[key] = cutUTF8(s, len(s)-over)
+		}
+	}
+
+	// укорачивать больше нечего, а запись всё ещё велика: оставляем только признак
+	r.Message = ""
+	return l.renderTruncated(r, map[string]any{truncatedMarker: true})
+}
+
+// largestNonString возвращает нестроковое поле с самым длинным значением
+// в формате логгера и длину этого значения в байтах
+func (l *Logger) largestNonString(fields map[string]any) (string, int) {
+	key, size := "", 0
+	for k, v := range fields {
+		if _, ok := v.(string); ok || k == truncatedMarker {
+			continue
+		}
+		// ключ и обрамление одинаковы, разница — длина значения без `""`
+		n := len(l.encodeFields(map[string]any{k: v})) - len(l.encodeFields(map[string]any{k: ""})) + 2
+		if n > size {
+			key, size = k, n
+		}
+	}
+	return key, size
+}
+
+// renderTruncated рендерит запись с заменёнными полями, без кэша полей логгера
+func (l *Logger) renderTruncated(r record, fields map[string]any) string {
+	r.static = &fieldCache{}
+	r.extra = maps.Clone(fields)
+	r.Fields = fields
+	return l.renderRecord(r)
+}
+
+// cutUTF8 обрезает s до n байт, не разрывая символ UTF-8
+func cutUTF8(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
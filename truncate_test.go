@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTruncateReplacesLargeNonString(t *testing.T) {
+	var b strings.Builder
+	l := New(Config{Output: &b, Format: FormatJSON, DisableTimestamp: true, MaxLineBytes: 200})
+
+	ids := make([]int, 500)
+	l.WithFields(map[string]any{"request_id": "abc123", "attempt": 3, "ids": ids}).Info("batch")
+
+	line := strings.TrimSpace(b.String())
+	if len(line) > 200 {
+		t.Fatalf("line is %d bytes, want at most 200: %s", len(line), line)
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		t.Fatalf("invalid JSON %s: %v", line, err)
+	}
+	if m["request_id"] != "abc123" || m["attempt"] != 3.0 || m["message"] != "batch" {
+		t.Fatalf("small fields must be kept: %s", line)
+	}
+	if m["ids"] != truncatedValue || m[truncatedMarker] != true {
+		t.Fatalf("want ids replaced and truncated=true: %s", line)
+	}
+
+	// превышение, вызванное ids, не должно срезать строку: она занимает
+	// всё место, оставшееся после замены ids
+	b.Reset()
+	l.WithFields(map[string]any{"reason": strings.Repeat("r", 300), "ids": make([]int, 100)}).Info("batch")
+
+	line = strings.TrimSpace(b.String())
+	m = nil
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		t.Fatalf("invalid JSON %s: %v", line, err)
+	}
+	if m["ids"] != truncatedValue {
+		t.Fatalf("want ids replaced: %s", line)
+	}
+	if len(line) < 195 || len(line) > 200 {
+		t.Fatalf("line is %d bytes, want close to 200: %s", len(line), line)
+	}
+	if reason := m["reason"].(string); len(reason) < 90 {
+		t.Fatalf("reason cut to %d bytes, more would fit: %s", len(reason), line)
+	}
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// fieldValue приводит значение поля к виду для вывода одинаково во всех форматах,
+// см. также RegisterCoercer
+func fieldValue(v any) any {
+	switch val := v.(type) {
+	case error:
+		if isNil(v) {
+			// Error() у nil-указателя обычно паникует
+			return nil
+		}
+		// иначе JSON выведет ошибку как структуру, чаще всего {}
+		return val.Error()
+	}
+	return coerce(v)
+}
+
+// jsonFieldValue значение поля для JSON. Типы с MarshalJSON или MarshalText
+// сериализуются сами, остальные fmt.Stringer пишутся строкой — так же, как в тексте.
+func (l *Logger) jsonFieldValue(v any) any {
+	v = fieldValue(v)
+	if nv, ok := l.nilValue(v, false); ok {
+		return nv
+	}
+	if l.ignoreStringer {
+		return v
+	}
+
+	switch v.(type) {
+	case json.Marshaler, encoding.TextMarshaler:
+		return v
+	case fmt.Stringer:
+		// fmt.Sprint перехватывает панику String() на nil-указателе
+		return fmt.Sprint(v)
+	}
+	return v
+}
+
+// textFieldValue значение поля для текста. fmt.Stringer выводится через %v,
+// тип только с MarshalJSON — своим JSON, как в JSON-формате, а map —
+// в виде {a:1 b:2} с ключами по порядку.
+func (l *Logger) textFieldValue(v any) any {
+	return l.textValue(v, 0)
+}
+
+func (l *Logger) textValue(v any, depth int) any {
+	v = fieldValue(v)
+	if nv, ok := l.nilValue(v, true); ok {
+		return nv
+	}
+	switch val := v.(type) {
+	case json.RawMessage:
+		return string(val)
+	case fmt.Stringer, fmt.Formatter:
+		return v
+	case json.Marshaler:
+		if l.ignoreStringer {
+			return v
+		}
+		if data, err := val.MarshalJSON(); err == nil {
+			return string(data)
+		}
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Map && depth < maxStructDepth {
+		return l.textMap(rv, depth)
+	}
+	return v
+}
+
+// textMap выводит map как {k:v k:v} с отсортированными ключами;
+// вложенные значения выводятся так же, как поля
+func (l *Logger) textMap(rv reflect.Value, depth int) string {
+	keys := rv.MapKeys()
+	slices.SortFunc(keys, compareKeys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%v:%v", k.Interface(), l.textValue(rv.MapIndex(k).Interface(), depth+1))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// compareKeys упорядочивает ключи map: числа и строки по значению,
+// остальные — по текстовому представлению
+func compareKeys(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	}
+	return cmp.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
@@ -0,0 +1,290 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// output общий для логгера и его потомков выход:
+// при необходимости буферизует запись и периодически сбрасывает буфер
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer     // куда пишутся строки
+	raw    io.Writer     // исходный писатель, под gzip и буфером
+	buf    *bufio.Writer // nil, если буферизация выключена
+	gz     *gzip.Writer  // nil, если сжатие потока выключено
+	closer io.Closer     // nil, если выход закрывать не нужно (stdout)
+	sync   func() error  // сбрасывает данные на диск, nil — нечего синхронизировать
+	reopen func() error  // переоткрывает файл, nil — выход не файловый
+	drain  func() error  // ждёт вывода фоновой очереди, nil — очереди нет
+	stop   chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+// outputOptions настройки цепочки записи
+type outputOptions struct {
+	flushInterval time.Duration // > 0 — буфер и фоновый сброс с этим периодом
+	bufferSize    int           // > 0 — буфер этого размера, см. WriteBufferSize
+	gzip          bool          // сжимать поток на лету
+	sync          func() error  // fsync выхода, см. fileSyncer и writerSyncer
+	reopen        func() error  // переоткрытие файла, см. Reopen
+	drain         func() error  // вывод фоновой очереди, см. NonBlockingStdout
+}
+
+// defaultGzipFlushInterval период сброса gzip-потока, если FlushInterval не задан
+const defaultGzipFlushInterval = time.Second
+
+// newOutput оборачивает w: при необходимости в gzip-поток и буфер,
+// и запускает фоновый сброс
+func newOutput(w io.Writer, closer io.Closer, opts outputOptions) *output {
+	o := &output{w: w, raw: w, closer: closer, sync: opts.sync, reopen: opts.reopen, drain: opts.drain}
+
+	if opts.gzip {
+		o.gz = gzip.NewWriter(o.w)
+		o.w = o.gz
+		if opts.flushInterval <= 0 {
+			// без периодического сброса поток нельзя прочитать до Close
+			opts.flushInterval = defaultGzipFlushInterval
+		}
+	}
+	if opts.flushInterval > 0 || opts.bufferSize > 0 {
+		o.buf = bufio.NewWriterSize(o.w, opts.bufferSize)
+		o.w = o.buf
+	}
+	if opts.flushInterval > 0 {
+		o.stop = make(chan struct{})
+		o.done = make(chan struct{})
+		go o.flushLoop(opts.flushInterval)
+	}
+	return o
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.w.Write(p)
+}
+
+// writeSync пишет p и сразу сбрасывает буферы. Очередь NonBlockingStdout
+// такую запись не отбрасывает, а ждёт её вывода, см. queueWriter.writeSync.
+func (o *output) writeSync(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if q, ok := o.raw.(*queueWriter); ok && o.gz == nil {
+		// drain не нужен: очередь и так выводит строки по порядку
+		if o.buf != nil {
+			if err := o.buf.Flush(); err != nil {
+				return 0, err
+			}
+		}
+		return q.writeSync(p)
+	}
+
+	n, err := o.w.Write(p)
+	if ferr := o.flush(); err == nil {
+		err = ferr
+	}
+	return n, err
+}
+
+// Flush сбрасывает буфер и gzip-поток, если они есть
+func (o *output) Flush() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.flush()
+}
+
+func (o *output) flush() error {
+	if o.buf != nil {
+		if err := o.buf.Flush(); err != nil {
+			return err
+		}
+	}
+	if o.gz != nil {
+		if err := o.gz.Flush(); err != nil {
+			return err
+		}
+	}
+	if o.drain != nil {
+		return o.drain()
+	}
+	return nil
+}
+
+// stdout сообщает, пишет ли выход в stdout, напрямую или через очередь
+func (o *output) stdout() bool {
+	if q, ok := o.raw.(*queueWriter); ok {
+		return q.w == os.Stdout
+	}
+	return o.raw == os.Stdout
+}
+
+// Sync сбрасывает буферы и синхронизирует выход с диском
+func (o *output) Sync() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := o.flush(); err != nil {
+		return err
+	}
+	if o.sync == nil {
+		return nil
+	}
+	return o.sync()
+}
+
+// Reopen сбрасывает буферы и переоткрывает файл. gzip-поток завершается
+// в старом файле и начинается заново в новом.
+func (o *output) Reopen() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.reopen == nil || o.closed {
+		return nil
+	}
+	if err := o.flush(); err != nil {
+		return err
+	}
+	if o.gz != nil {
+		if err := o.gz.Close(); err != nil {
+			return err
+		}
+		defer o.gz.Reset(o.raw)
+	}
+	return o.reopen()
+}
+
+// replace переключает выход на w с сохранением сжатия и буферизации.
+// Прежний писатель сбрасывается и закрывается; записи, отложенные
+// в deferredBuffer, переносятся в w.
+func (o *output) replace(w io.Writer) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return nil
+	}
+
+	var pending []byte
+	if d, ok := o.raw.(*deferredBuffer); ok {
+		pending = d.buf.Bytes()
+		o.closer = nil // отложенное уйдёт в w, а не в stderr
+	}
+
+	err := o.flush()
+	if o.gz != nil {
+		if gerr := o.gz.Close(); err == nil {
+			err = gerr
+		}
+	}
+	if o.closer != nil {
+		if cerr := o.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	o.w, o.raw, o.closer = w, w, nil
+	o.sync, o.reopen, o.drain = writerSyncer(w), nil, nil
+	if o.gz != nil {
+		o.gz.Reset(o.w)
+		o.w = o.gz
+	}
+	if o.buf != nil {
+		o.buf.Reset(o.w)
+		o.w = o.buf
+	}
+
+	if len(pending) > 0 {
+		if _, werr := o.w.Write(pending); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// fileSyncer делает fsync файла по пути. Файл lumberjack недоступен снаружи,
+// но fsync по любому дескриптору сбрасывает на диск данные всего файла.
+func fileSyncer(path string) func() error {
+	return func() error {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // ещё ничего не записано
+		}
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return f.Sync()
+	}
+}
+
+// writerSyncer возвращает Sync писателя, если он есть. Для stdout и stderr
+// синхронизация не нужна, а на терминалах и pipe возвращает ошибку.
+func writerSyncer(w io.Writer) func() error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if s, ok := w.(interface{ Sync() error }); ok {
+		return s.Sync
+	}
+	return nil
+}
+
+// Close останавливает фоновый сброс, сбрасывает буфер, завершает gzip-поток и закрывает выход
+func (o *output) Close() error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	o.mu.Unlock()
+
+	if o.stop != nil {
+		close(o.stop)
+		<-o.done
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	err := o.flush()
+	if o.gz != nil {
+		// дописывает завершающий блок gzip
+		if gerr := o.gz.Close(); err == nil {
+			err = gerr
+		}
+	}
+	if o.closer != nil {
+		if cerr := o.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (o *output) flushLoop(interval time.Duration) {
+	defer close(o.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			o.Flush()
+		case <-o.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package zapadapter реализует zapcore.Core поверх vira-logger, чтобы код,
+// ожидающий zap, писал через общий логгер с его выводом и ротацией.
+//
+// Пакет вынесен в отдельный модуль, поэтому ядро логгера не зависит от zap.
+package zapadapter
+
+import (
+	"path/filepath"
+	"strconv"
+
+	logger "github.com/skrolikov/vira-logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// Core реализует zapcore.Core
+type Core struct {
+	l *logger.Logger
+}
+
+// NewCore создаёт zapcore.Core, пишущий в l:
+//
+//	zl := zap.New(zapadapter.NewCore(log), zap.AddCaller())
+func NewCore(l *logger.Logger) *Core {
+	return &Core{l: l}
+}
+
+// Level переводит уровень zap в уровень логгера. DPanic и Panic пишутся как
+// ERROR (панику затем вызывает сам zap), Fatal — как FATAL.
+func Level(level zapcore.Level) logger.Level {
+	switch {
+	case level >= zapcore.FatalLevel:
+		return logger.FATAL
+	case level >= zapcore.ErrorLevel:
+		return logger.ERROR
+	case level >= zapcore.WarnLevel:
+		return logger.WARN
+	case level >= zapcore.InfoLevel:
+		return logger.INFO
+	default:
+		return logger.DEBUG
+	}
+}
+
+// Enabled проверяет уровень по порогу логгера
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.l.Enabled(Level(level))
+}
+
+// With возвращает Core с дополнительными полями
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	if len(fields) == 0 {
+		return c
+	}
+	return &Core{l: c.l.WithFields(encodeFields(fields))}
+}
+
+// Check добавляет Core к записи, если уровень включён
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write пишет запись zap. Время и место вызова берутся из записи zap,
+// имя логгера пишется в поле logger, стек — в stacktrace. Fatal не завершает
+// процесс здесь: после Write это делает сам zap (или его WithFatalHook),
+// поэтому запись уровней выше Error сразу сбрасывается на диск.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	m := encodeFields(fields)
+	if ent.LoggerName != "" {
+		m["logger"] = ent.LoggerName
+	}
+	if ent.Stack != "" {
+		m["stacktrace"] = ent.Stack
+	}
+
+	e := logger.Entry{Time: ent.Time, Message: ent.Message, Fields: m}
+	if ent.Caller.Defined {
+		e.Caller = filepath.Base(ent.Caller.File) + ":" + strconv.Itoa(ent.Caller.Line)
+	}
+
+	c.l.LogBatchNoExit(Level(ent.Level), []logger.Entry{e})
+	if ent.Level > zapcore.ErrorLevel {
+		return c.l.Sync()
+	}
+	return nil
+}
+
+// Sync сбрасывает буферы логгера
+func (c *Core) Sync() error {
+	return c.l.Sync()
+}
+
+// encodeFields переводит поля zap в map; пространства имён становятся вложенными объектами
+func encodeFields(fields []zapcore.Field) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
@@ -0,0 +1,25 @@
+package zapadapter
+
+import (
+	"strings"
+	"testing"
+
+	logger "github.com/skrolikov/vira-logger"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFatalDoesNotExit(t *testing.T) {
+	var b strings.Builder
+	exited := false
+	l := logger.New(logger.Config{Output: &b, DisableTimestamp: true, ExitFunc: func(int) { exited = true }})
+
+	if err := NewCore(l).Write(zapcore.Entry{Level: zapcore.FatalLevel, Message: "boom"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if exited {
+		t.Fatal("Write must leave process termination to zap")
+	}
+	if !strings.Contains(b.String(), "[FATAL]") || !strings.Contains(b.String(), "boom") {
+		t.Fatalf("fatal entry not written: %q", b.String())
+	}
+}
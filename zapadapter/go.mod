@@ -0,0 +1,15 @@
+module github.com/skrolikov/vira-logger/zapadapter
+
+go 1.24.3
+
+require (
+	github.com/skrolikov/vira-logger v0.0.0
+	go.uber.org/zap v1.28.0
+)
+
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+replace github.com/skrolikov/vira-logger => ../